@@ -2,165 +2,312 @@ package dcache
 
 import (
 	"container/list"
-	"sync"
+	"context"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"io"
+	"sync/atomic"
 	"time"
 )
 
+/*
+Cache - is a concurrent cache with per item time-to-live and pluggable eviction policy, see Policy. Keys are spread
+over independently locked shards, so operations on keys of different shards don't contend. Reads never take a write
+lock, see policy.hit().
+*/
 type Cache[K comparable, V any] interface {
 	options[K, V]
 
-	Set(key K, val V, ttl time.Duration)
+	Set(key K, val V, ttl time.Duration, tags ...string)
 	Get(key K) (V, bool)
+	GetWithExpiry(key K) (V, time.Time, bool)
+	Peek(key K) (V, time.Time, bool)
+	Touch(key K, ttl time.Duration) bool
+	Keys() []K
+	Range(f func(key K, val V, expiredAt time.Time) bool)
+	GetOrLoad(ctx context.Context, key K, loader Loader[V]) (V, error)
 	DeleteExpiredCache()
+	Running(ctx context.Context) error
 	Len() int
+	Weight() int64
 	InvalidateKey(key K)
+	InvalidateTag(tag string)
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
 
-	removeItem(e *list.Element)
-	removeBack()
-	removeBackIfExpired()
+	shardOf(key K) *shard[K, V]
+	invalidateFunc(match func(key K) bool)
 }
 
 const weekDuration = time.Hour * 24 * 7
 
+const (
+	// shardsDefaultN - is a default number of shards. See WithShards().
+	shardsDefaultN = 16
+	/*
+		shardCapacityMin - is a minimal number of items each shard is expected to hold. Limited caches get fewer shards
+		if needed, so small capacities are still evicted in a strict LRU order.
+	*/
+	shardCapacityMin = 16
+)
+
 func NewCache[K comparable, V any]() Cache[K, V] {
-	return &cache[K, V]{
-		data:     make(map[K]*list.Element),
-		items:    list.New(),
+	c := &cache[K, V]{
 		capacity: -1,
 		ttl:      weekDuration,
+		shardsN:  shardsDefaultN,
+		hasher:   hasherOf[K](),
+		loads:    newLoads[K, V](),
+		codec:    CodecGob,
+		log:      dlog.New().With().Name("dcache").Build(),
+		clock:    dtime.ClockReal,
+
+		janitorInterval: janitorIntervalDefault,
 	}
+	c.resetShards()
+
+	return c
 }
 
-// Set - ttl == 0 equals time.Hour * 24 * 7 (one week).
-func (c *cache[K, V]) Set(key K, val V, ttl time.Duration) {
-	c.mu.Lock()
+/*
+Set - ttl == 0 equals time.Hour * 24 * 7 (one week). A value heavier than the max weight is not cached, and a previous
+'key' value is dropped, see WithMaxWeight(). If refresh is set, 'ttl' is a hard one, see WithRefresh(). 'tags' replace
+the previous 'key' ones, see Cache.InvalidateTag().
+*/
+func (c *cache[K, V]) Set(key K, val V, ttl time.Duration, tags ...string) {
+	c.set(key, val, ttl, tags, false)
+}
 
+/*
+set - see Cache.Set(). If 'keepTags' is true, a replaced item keeps its tags instead of 'tags'. It is used to write
+loaded values back, so refreshed items are still invalidated by their tags.
+*/
+func (c *cache[K, V]) set(key K, val V, ttl time.Duration, tags []string, keepTags bool) {
 	if ttl <= 0 {
 		ttl = c.ttl
 	}
 
-	now := time.Now()
-	if el, ok := c.data[key]; ok {
-		el.Value.(*item[K, V]).val = val
-		el.Value.(*item[K, V]).expiredAt = now.Add(ttl)
-
-		c.items.MoveToFront(el)
+	var (
+		h      = c.hash(key)
+		i      = c.shardI(h)
+		weight = c.weigh(key, val)
+	)
 
-		c.mu.Unlock()
+	if c.maxWeight > 0 && weight > c.maxWeight {
+		c.shards[i].invalidate(key, RemovalReasonReplaced)
 		return
 	}
 
-	el := &item[K, V]{
-		key:       key,
-		val:       val,
-		expiredAt: now.Add(ttl),
-	}
-
-	element := c.items.PushFront(el)
-	c.data[key] = element
+	c.metrics.set()
 
-	c.removeBackIfExpired()
+	var (
+		now       = c.clock.Now()
+		refreshAt time.Time
+	)
 
-	if c.capacity >= 0 && c.items.Len() > c.capacity {
-		c.removeBack()
+	if c.refresh != nil && c.softTTL > 0 && c.softTTL < ttl {
+		refreshAt = now.Add(c.softTTL)
 	}
 
-	c.mu.Unlock()
+	var added = c.shards[i].set(key, h, val, weight, now, now.Add(ttl), refreshAt, tags, keepTags)
+
+	if added {
+		c.evictOverCapacity(i)
+	}
 }
 
 func (c *cache[K, V]) Get(key K) (V, bool) {
-	c.mu.RLock()
+	val, _, ok := c.GetWithExpiry(key)
+	return val, ok
+}
 
-	result := *new(V)
-	if el, ok := c.data[key]; ok {
-		if time.Now().After(el.Value.(*item[K, V]).expiredAt) {
-			c.mu.RUnlock()
-			return result, false
-		}
+// GetWithExpiry - is the same as Cache.Get(), but returns value expiration time as well.
+func (c *cache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	var (
+		h   = c.hash(key)
+		now = c.clock.Now()
+	)
 
-		c.items.MoveToFront(el)
+	val, expiredAt, ok, stale := c.shards[c.shardI(h)].get(key, h, now)
+	c.metrics.get(ok)
 
-		c.mu.RUnlock()
-		return el.Value.(*item[K, V]).val, true
+	if stale {
+		c.refreshAhead(key, now)
 	}
 
-	c.mu.RUnlock()
-	return result, false
+	return val, expiredAt, ok
 }
 
 func (c *cache[K, V]) DeleteExpiredCache() {
-	c.mu.Lock()
+	var now = c.clock.Now()
 
-	for it := c.items.Front(); it != nil; it = it.Next() {
-		if time.Now().After(it.Value.(*item[K, V]).expiredAt) {
-			c.removeItem(it)
-		}
+	for _, s := range c.shards {
+		s.deleteExpired(now, 0)
 	}
 
-	c.mu.Unlock()
+	c.loads.deleteExpired(now)
 }
 
 func (c *cache[K, V]) Len() int {
-	c.mu.RLock()
-	lens := len(c.data)
-	c.mu.RUnlock()
-	return lens
+	var n int
+	for _, s := range c.shards {
+		n += s.len()
+	}
+
+	return n
+}
+
+// Weight - returns a total weight of cached items. It is always 0 unless a weigher is set, see WithMaxWeight().
+func (c *cache[K, V]) Weight() int64 {
+	return c.usage.weight.Load()
 }
 
 func (c *cache[K, V]) InvalidateKey(key K) {
-	c.mu.Lock()
-	if el, ok := c.data[key]; ok {
-		c.removeItem(el)
+	c.shardOf(key).invalidate(key, RemovalReasonInvalidated)
+}
+
+/*
+evictOverCapacity - evicts items until cache fits its capacity and max weight. Shard 'i' is evicted first, then the
+following ones if it becomes empty.
+*/
+func (c *cache[K, V]) evictOverCapacity(i int) {
+	if c.capacity < 0 && c.maxWeight < 1 {
+		return
+	}
+
+	for j := 0; j < len(c.shards) && c.overCapacity(); {
+		if !c.shards[(i+j)%len(c.shards)].evict() {
+			j++
+		}
 	}
-	c.mu.Unlock()
 }
 
-func (c *cache[K, V]) removeItem(e *list.Element) {
-	c.items.Remove(e)
-	kv := e.Value.(*item[K, V]) //nolint:errcheck
-	delete(c.data, kv.key)
+func (c *cache[K, V]) overCapacity() bool {
+	if c.capacity >= 0 && c.usage.n.Load() > int64(c.capacity) {
+		return true
+	}
+
+	return c.maxWeight > 0 && c.usage.weight.Load() > c.maxWeight
 }
 
-func (c *cache[K, V]) removeBack() {
-	element := c.items.Back()
-	if element != nil {
-		c.removeItem(element)
+// weigh - returns 'val' weight, see WithMaxWeight(). Negative weight is considered to be 0.
+func (c *cache[K, V]) weigh(key K, val V) int64 {
+	if c.weigher == nil {
+		return 0
 	}
+
+	return max(0, c.weigher(key, val))
+}
+
+func (c *cache[K, V]) shardOf(key K) *shard[K, V] {
+	return c.shards[c.shardI(c.hash(key))]
+}
+
+// hash - returns 'key' hash by a hasher, see hasherOf() and WithHasher().
+func (c *cache[K, V]) hash(key K) uint64 {
+	return c.hasher(key)
 }
 
-func (c *cache[K, V]) removeBackIfExpired() {
-	element := c.items.Back()
-	if element != nil && time.Now().After(element.Value.(*item[K, V]).expiredAt) {
-		c.removeItem(element)
+func (c *cache[K, V]) shardI(h uint64) int {
+	return int(h % uint64(len(c.shards)))
+}
+
+// resetShards - (re)creates empty shards according to configuration. Existing items are dropped.
+func (c *cache[K, V]) resetShards() {
+	var n = c.shardsN
+	if c.capacity >= 0 {
+		n = min(n, max(1, c.capacity/shardCapacityMin))
+	}
+
+	var shardCapacity = -1
+	if c.capacity >= 0 {
+		shardCapacity = (c.capacity + n - 1) / n
+	}
+
+	c.usage.n.Store(0)
+	c.usage.weight.Store(0)
+	c.shards = make([]*shard[K, V], 0, n)
+	for i := 0; i < n; i++ {
+		c.shards = append(c.shards, newShard[K, V](&c.usage, newPolicy[K, V](c.policy, shardCapacity), c.shardsListener()))
+	}
+}
+
+func (c *cache[K, V]) resetShardsListener() {
+	var listener = c.shardsListener()
+
+	for _, s := range c.shards {
+		s.listener = listener
+	}
+}
+
+// shardsListener - returns a listener which counts removals if metrics are set and then notifies cache listener.
+func (c *cache[K, V]) shardsListener() Listener[K, V] {
+	if c.metrics == nil {
+		return c.listener
+	}
+
+	return func(key K, val V, reason RemovalReason) {
+		c.metrics.removed(reason)
+
+		if c.listener != nil {
+			c.listener(key, val, reason)
+		}
 	}
 }
 
 type cache[K comparable, V any] struct {
-	mu   sync.RWMutex
-	data map[K]*list.Element
+	shards []*shard[K, V]
+	// usage - is a total number and weight of items across all shards.
+	usage usage
 
+	loads    *loads[K, V]
+	listener Listener[K, V]
+	metrics  *metrics
+	codec    Codec
+
+	shardsN  int
+	hasher   func(key K) uint64
 	capacity int
+	policy   Policy
 	ttl      time.Duration
-	items    *list.List
+	errTTL   time.Duration
+
+	// maxWeight - limits total weight of items if > 0. See WithMaxWeight().
+	maxWeight int64
+	weigher   func(key K, val V) int64
+
+	// softTTL - is a time after which items are refreshed by 'refresh' if it is set. See WithRefresh().
+	softTTL time.Duration
+	refresh KeyLoader[K, V]
+	log     dlog.Logger
+	clock   dtime.Clock
+
+	janitorInterval time.Duration
 }
 
 type item[K comparable, V any] struct {
 	expiredAt time.Time
+	// refreshAt - is zero if item is not to be refreshed. See WithRefresh().
+	refreshAt time.Time
 	key       K
+	tags      []string
 	val       V
-}
-
-type options[K comparable, V any] interface {
-	WithCapacity(capacity int) Cache[K, V]
-	WithDuration(ttl time.Duration) Cache[K, V]
-}
-
-func (c *cache[K, V]) WithCapacity(capacity int) Cache[K, V] {
-	c.capacity = capacity
-	return c
-}
-
-func (c *cache[K, V]) WithDuration(ttl time.Duration) Cache[K, V] {
-	c.ttl = ttl
-	return c
+	hash      uint64
+	weight    int64
+	// expiryI - is an index within shard expiries.
+	expiryI int
+
+	// Policies data. See policy.
+
+	// accessed - is set by readers under shard read lock.
+	accessed atomic.Bool
+	// hits - is incremented by readers under shard read lock.
+	hits atomic.Uint32
+	// el - is an element of a policy list.
+	el *list.Element
+	// policyI - is an index within a policy heap.
+	policyI int
+	freq    uint32
+	seq     uint64
+	segment uint8
 }
@@ -0,0 +1,141 @@
+package dcache
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const benchKeysN = 1 << 14
+
+var benchKeys = func() []string {
+	var keys = make([]string, 0, benchKeysN)
+	for i := 0; i < benchKeysN; i++ {
+		keys = append(keys, "key_"+strconv.Itoa(i))
+	}
+
+	return keys
+}()
+
+func BenchmarkCache_GetParallel(b *testing.B) {
+	benchGetParallel(b, NewCache[string, int]())
+}
+
+func BenchmarkLockedCache_GetParallel(b *testing.B) {
+	benchGetParallel(b, newLockedCache[string, int]())
+}
+
+func BenchmarkCache_GetSetParallel(b *testing.B) {
+	benchGetSetParallel(b, NewCache[string, int]().WithCapacity(benchKeysN/2))
+}
+
+func BenchmarkLockedCache_GetSetParallel(b *testing.B) {
+	benchGetSetParallel(b, newLockedCache[string, int]().withCapacity(benchKeysN/2))
+}
+
+type benchCache interface {
+	Set(key string, val int, ttl time.Duration, tags ...string)
+	Get(key string) (int, bool)
+}
+
+func benchGetParallel(b *testing.B, cache benchCache) {
+	for i, key := range benchKeys {
+		cache.Set(key, i, 0)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			_, _ = cache.Get(benchKeys[i%benchKeysN])
+		}
+	})
+}
+
+// benchGetSetParallel - 90% of reads and 10% of writes.
+func benchGetSetParallel(b *testing.B, cache benchCache) {
+	for i, key := range benchKeys {
+		cache.Set(key, i, 0)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			var key = benchKeys[(i*7)%benchKeysN]
+
+			if i%10 == 0 {
+				cache.Set(key, i, 0)
+
+				continue
+			}
+
+			_, _ = cache.Get(key)
+		}
+	})
+}
+
+/*
+lockedCache - is a previous single lock cache implementation kept as a benchmark baseline. Get() takes a write lock,
+because promoting an item under read lock is a data race.
+*/
+type lockedCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	data     map[K]*list.Element
+	items    *list.List
+	capacity int
+}
+
+func newLockedCache[K comparable, V any]() *lockedCache[K, V] {
+	return &lockedCache[K, V]{
+		data:     make(map[K]*list.Element),
+		items:    list.New(),
+		capacity: -1,
+	}
+}
+
+func (c *lockedCache[K, V]) withCapacity(capacity int) *lockedCache[K, V] {
+	c.capacity = capacity
+	return c
+}
+
+func (c *lockedCache[K, V]) Set(key K, val V, ttl time.Duration, _ ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		ttl = weekDuration
+	}
+
+	if el, ok := c.data[key]; ok {
+		el.Value.(*item[K, V]).val = val
+		el.Value.(*item[K, V]).expiredAt = time.Now().Add(ttl)
+		c.items.MoveToFront(el)
+
+		return
+	}
+
+	c.data[key] = c.items.PushFront(&item[K, V]{key: key, val: val, expiredAt: time.Now().Add(ttl)})
+
+	if c.capacity >= 0 && c.items.Len() > c.capacity {
+		el := c.items.Back()
+		c.items.Remove(el)
+		delete(c.data, el.Value.(*item[K, V]).key)
+	}
+}
+
+func (c *lockedCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok || time.Now().After(el.Value.(*item[K, V]).expiredAt) {
+		return *new(V), false
+	}
+
+	c.items.MoveToFront(el)
+
+	return el.Value.(*item[K, V]).val, true
+}
@@ -2,9 +2,11 @@ package dcache
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/don-nv/go-dpkg/dtime/v1"
 	"github.com/stretchr/testify/require"
 )
 
@@ -81,3 +83,120 @@ func TestCache_InvalidateKey(t *testing.T) {
 	require.EqualValues(t, false, ok)
 	require.Equal(t, 0, k2)
 }
+
+func TestCache_Concurrent(t *testing.T) {
+	const (
+		goroutinesN = 8
+		keysN       = 1000
+		capacity    = 500
+	)
+
+	cache := NewCache[int, int]().
+		WithCapacity(capacity)
+
+	var wg sync.WaitGroup
+
+	wg.Add(goroutinesN)
+	for g := 0; g < goroutinesN; g++ {
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < keysN; i++ {
+				var key = (i * (g + 1)) % keysN
+
+				if i%3 == 0 {
+					cache.Set(key, i, 0)
+				}
+
+				if v, ok := cache.Get(key); ok {
+					require.GreaterOrEqual(t, v, 0)
+				}
+
+				if i%100 == 0 {
+					cache.InvalidateKey(key)
+					cache.DeleteExpiredCache()
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	require.LessOrEqual(t, cache.Len(), capacity)
+}
+
+func TestCache_CapacityLRU(t *testing.T) {
+	cache := NewCache[string, int]().
+		WithCapacity(3)
+
+	cache.Set("key1", 1, 0)
+	cache.Set("key2", 2, 0)
+	cache.Set("key3", 3, 0)
+
+	_, ok := cache.Get("key1")
+	require.True(t, ok)
+
+	cache.Set("key4", 4, 0)
+
+	_, ok = cache.Get("key2")
+	require.False(t, ok)
+
+	for _, key := range []string{"key1", "key3", "key4"} {
+		_, ok = cache.Get(key)
+		require.True(t, ok, key)
+	}
+}
+
+func TestCache_WithMaxWeight(t *testing.T) {
+	cache := NewCache[string, string]().
+		WithShards(1).
+		WithMaxWeight(10, func(key string, val string) int64 {
+			return int64(len(val))
+		})
+
+	cache.Set("key1", "aaaa", 0)
+	cache.Set("key2", "bbbb", 0)
+	require.EqualValues(t, 8, cache.Weight())
+
+	cache.Set("key3", "cccc", 0)
+	require.EqualValues(t, 8, cache.Weight())
+	require.EqualValues(t, 2, cache.Len())
+
+	_, ok := cache.Get("key1")
+	require.False(t, ok)
+
+	cache.Set("key2", "b", 0)
+	require.EqualValues(t, 5, cache.Weight())
+
+	cache.Set("key2", "too heavy to be cached", 0)
+	_, ok = cache.Get("key2")
+	require.False(t, ok)
+	require.EqualValues(t, 4, cache.Weight())
+	require.EqualValues(t, 1, cache.Len())
+}
+
+func TestCache_WithClock(t *testing.T) {
+	var (
+		clock = dtime.NewFakeClock(time.Now())
+		cache = NewCache[string, int]().WithClock(clock)
+	)
+
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Hour)
+
+	clock.Advance(time.Minute - time.Second)
+
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	clock.Advance(2 * time.Second)
+
+	_, ok = cache.Get("a")
+	require.False(t, ok)
+
+	_, ok = cache.Get("b")
+	require.True(t, ok)
+
+	cache.DeleteExpiredCache()
+	require.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,50 @@
+package dcache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiries - is a min-heap of items ordered by expiration time. It is used to reclaim expired items without a full scan.
+type expiries[K comparable, V any] []*item[K, V]
+
+var _ heap.Interface = (*expiries[int, int])(nil)
+
+func (e expiries[K, V]) Len() int { return len(e) }
+
+func (e expiries[K, V]) Less(i, j int) bool { return e[i].expiredAt.Before(e[j].expiredAt) }
+
+func (e expiries[K, V]) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+	e[i].expiryI = i
+	e[j].expiryI = j
+}
+
+func (e *expiries[K, V]) Push(x any) {
+	it := x.(*item[K, V]) //nolint:errcheck
+	it.expiryI = len(*e)
+	*e = append(*e, it)
+}
+
+func (e *expiries[K, V]) Pop() any {
+	var (
+		old = *e
+		n   = len(old)
+		it  = old[n-1]
+	)
+
+	old[n-1] = nil
+	it.expiryI = -1
+	*e = old[:n-1]
+
+	return it
+}
+
+// expired - returns the soonest expiring item if it has expired at 'now'.
+func (e expiries[K, V]) expired(now time.Time) (*item[K, V], bool) {
+	if len(e) < 1 || !now.After(e[0].expiredAt) {
+		return nil, false
+	}
+
+	return e[0], true
+}
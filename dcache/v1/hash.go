@@ -0,0 +1,178 @@
+package dcache
+
+import (
+	"math"
+	"reflect"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// keyHasher - is implemented by pointers to keys providing their hasher, e.g. *Key2 and *Key3. See hasherOf().
+type keyHasher interface {
+	// cacheHasher - returns func(key K) uint64, where K is a key type. It is called on a nil pointer.
+	cacheHasher() any
+}
+
+/*
+hasherOf - returns K hasher used to spread keys over shards. Keys equal by == always have the same hash: floats are
+normalized, so -0 and +0 are the same, while pointers and channels are hashed by address, but not by data they point
+to. Hashing of strings, numbers and bools is deterministic across processes, so the same key belongs to the same shard
+as long as shards number is unchanged. Keys of other kinds, e.g. structs and arrays, are hashed by their fields and
+elements using reflection, which is considerably slower and allocates, see WithHasher(). Key2 and Key3 are hashed
+without reflection as long as their arguments are.
+*/
+func hasherOf[K comparable]() func(key K) uint64 {
+	var f any
+
+	// Nil pointer is switched on, so no K value is converted to an interface.
+	switch p := any((*K)(nil)).(type) {
+	case *string:
+		f = hashString
+	case *int:
+		f = func(k int) uint64 { return hashUint64(uint64(k)) }
+	case *int8:
+		f = func(k int8) uint64 { return hashUint64(uint64(k)) }
+	case *int16:
+		f = func(k int16) uint64 { return hashUint64(uint64(k)) }
+	case *int32:
+		f = func(k int32) uint64 { return hashUint64(uint64(k)) }
+	case *int64:
+		f = func(k int64) uint64 { return hashUint64(uint64(k)) }
+	case *uint:
+		f = func(k uint) uint64 { return hashUint64(uint64(k)) }
+	case *uint8:
+		f = func(k uint8) uint64 { return hashUint64(uint64(k)) }
+	case *uint16:
+		f = func(k uint16) uint64 { return hashUint64(uint64(k)) }
+	case *uint32:
+		f = func(k uint32) uint64 { return hashUint64(uint64(k)) }
+	case *uint64:
+		f = hashUint64
+	case *uintptr:
+		f = func(k uintptr) uint64 { return hashUint64(uint64(k)) }
+	case *bool:
+		f = hashBool
+	case *float32:
+		f = func(k float32) uint64 { return hashFloat64(float64(k)) }
+	case *float64:
+		f = hashFloat64
+	case *complex64:
+		f = func(k complex64) uint64 { return hashComplex128(complex128(k)) }
+	case *complex128:
+		f = hashComplex128
+	case keyHasher:
+		f = p.cacheHasher()
+	default:
+		f = func(k K) uint64 { return hashValue(reflect.ValueOf(&k).Elem()) }
+	}
+
+	return f.(func(key K) uint64) //nolint:errcheck
+}
+
+// hash - hashes 'key' by hasherOf(). It is intended to hash a single key, otherwise reuse a hasher.
+func hash[K comparable](key K) uint64 {
+	return hasherOf[K]()(key)
+}
+
+// hashValue - hashes comparable 'v' by data it is compared by.
+func hashValue(v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.String:
+		return hashString(v.String())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return hashUint64(uint64(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return hashUint64(v.Uint())
+
+	case reflect.Bool:
+		return hashBool(v.Bool())
+
+	case reflect.Float32, reflect.Float64:
+		return hashFloat64(v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		return hashComplex128(v.Complex())
+
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return hashUint64(uint64(v.Pointer()))
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+
+		return hashValue(v.Elem())
+
+	case reflect.Array:
+		var h uint64 = fnvOffset64
+		for i := 0; i < v.Len(); i++ {
+			h = hashCombine(h, hashValue(v.Index(i)))
+		}
+
+		return h
+
+	case reflect.Struct:
+		var h uint64 = fnvOffset64
+		for i := 0; i < v.NumField(); i++ {
+			h = hashCombine(h, hashValue(v.Field(i)))
+		}
+
+		return h
+	}
+
+	// Other kinds are not comparable.
+	return 0
+}
+
+// hashString - is FNV-1a.
+func hashString(s string) uint64 {
+	var h uint64 = fnvOffset64
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+
+	return h
+}
+
+// hashUint64 - is a splitmix64 finalizer. Consecutive integers get spread evenly.
+func hashUint64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+func hashBool(b bool) uint64 {
+	if b {
+		return hashUint64(1)
+	}
+
+	return hashUint64(0)
+}
+
+// hashFloat64 - hashes -0 and +0 the same, since they are equal.
+func hashFloat64(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+
+	return hashUint64(math.Float64bits(f))
+}
+
+func hashComplex128(c complex128) uint64 {
+	return hashCombine(hashFloat64(real(c)), hashFloat64(imag(c)))
+}
+
+// hashCombine - mixes hash 'x' into hash 'h'. Order of mixed hashes matters.
+func hashCombine(h, x uint64) uint64 {
+	return hashUint64(h*fnvPrime64 ^ x)
+}
@@ -0,0 +1,93 @@
+package dcache
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hashStringer struct {
+	n int
+}
+
+func (h *hashStringer) String() string {
+	return strconv.Itoa(h.n)
+}
+
+type hashKey struct {
+	Name  string
+	Value float64
+	Ptr   *hashStringer
+	Any   any
+	Array [2]bool
+}
+
+func TestHash(t *testing.T) {
+	var negZero = math.Copysign(0, -1)
+
+	require.Equal(t, hash(0.0), hash(negZero))
+	require.Equal(t, hash(complex(0.0, 0.0)), hash(complex(negZero, negZero)))
+	require.Equal(t, hash(hashKey{Value: 0}), hash(hashKey{Value: negZero}))
+
+	var p = &hashStringer{n: 1}
+	var h = hash(p)
+
+	p.n = 2
+	require.Equal(t, h, hash(p))
+
+	var key = hashKey{Name: "a", Ptr: p, Any: 1, Array: [2]bool{true, false}}
+	require.Equal(t, hash(key), hash(hashKey{Name: "a", Ptr: p, Any: 1, Array: [2]bool{true, false}}))
+	require.NotEqual(t, hash(key), hash(hashKey{Name: "b", Ptr: p, Any: 1, Array: [2]bool{true, false}}))
+	require.NotEqual(t, hash(NewKey2(1, 2)), hash(NewKey2(2, 1)))
+}
+
+func TestCache_EqualKeys(t *testing.T) {
+	var floats = NewCache[float64, int]().WithShards(64)
+
+	floats.Set(math.Copysign(0, -1), 1, 0)
+	v, ok := floats.Get(0)
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+
+	var (
+		ptrs = NewCache[*hashStringer, int]().WithShards(64)
+		p    = &hashStringer{n: 1}
+	)
+
+	ptrs.Set(p, 1, 0)
+	p.n = 2
+
+	_, ok = ptrs.Get(p)
+	require.True(t, ok)
+
+	ptrs.Set(p, 2, 0)
+	require.Equal(t, 1, ptrs.Len())
+}
+
+func TestCache_Key2Allocations(t *testing.T) {
+	var (
+		cache = NewCache[Key2[string, int], int]()
+		key   = NewKey2("a", 1)
+	)
+
+	cache.Set(key, 1, 0)
+
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get(key)
+	}))
+}
+
+func TestCache_WithHasher(t *testing.T) {
+	var c = NewCache[hashKey, int]().WithShards(64).WithHasher(func(key hashKey) uint64 {
+		return hashString(key.Name)
+	})
+
+	c.Set(hashKey{Name: "a"}, 1, 0)
+
+	v, ok := c.Get(hashKey{Name: "a"})
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+	require.Equal(t, hashString("a"), c.(*cache[hashKey, int]).hash(hashKey{Name: "a"}))
+}
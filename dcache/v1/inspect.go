@@ -0,0 +1,115 @@
+package dcache
+
+import (
+	"container/heap"
+	"time"
+)
+
+/*
+Peek - returns not expired value with its expiration time, but unlike Cache.Get() it neither counts as a use for
+eviction policy and metrics nor starts a refresh.
+*/
+func (c *cache[K, V]) Peek(key K) (V, time.Time, bool) {
+	return c.shardOf(key).peek(key, c.clock.Now())
+}
+
+/*
+Touch - extends not expired value ttl, so it expires in 'ttl' from now. See Cache.Set() regarding ttl defaults. Returns
+false if the value is not found.
+*/
+func (c *cache[K, V]) Touch(key K, ttl time.Duration) bool {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	var now = c.clock.Now()
+
+	return c.shardOf(key).touch(key, now, now.Add(ttl))
+}
+
+// Keys - returns keys of not expired items. See Cache.Range() regarding consistency.
+func (c *cache[K, V]) Keys() []K {
+	var keys = make([]K, 0, c.usage.n.Load())
+
+	c.Range(func(key K, _ V, _ time.Time) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+/*
+Range - calls 'f' for each not expired item until 'f' returns false. Items are not promoted. Each shard is observed at
+once under its read lock, while 'f' is called without locks, so it may use the cache. Hence, items of a single shard
+are consistent, while items changed concurrently in shards not observed yet may or may not be observed.
+*/
+func (c *cache[K, V]) Range(f func(key K, val V, expiredAt time.Time) bool) {
+	var (
+		now   = c.clock.Now()
+		items []rangeItem[K, V]
+	)
+
+	for _, s := range c.shards {
+		items = s.rangeItems(items[:0], now)
+
+		for _, it := range items {
+			if !f(it.key, it.val, it.expiredAt) {
+				return
+			}
+		}
+	}
+}
+
+func (s *shard[K, V]) peek(key K, now time.Time) (V, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	it, ok := s.data[key]
+	if !ok || now.After(it.expiredAt) {
+		return *new(V), time.Time{}, false
+	}
+
+	return it.val, it.expiredAt, true
+}
+
+func (s *shard[K, V]) touch(key K, now, expiredAt time.Time) bool {
+	s.mu.Lock()
+	defer s.unlock()
+
+	it, ok := s.data[key]
+	if !ok || now.After(it.expiredAt) {
+		return false
+	}
+
+	it.expiredAt = expiredAt
+	heap.Fix(&s.expiries, it.expiryI)
+
+	return true
+}
+
+// rangeItems - appends not expired items to 'items'.
+func (s *shard[K, V]) rangeItems(items []rangeItem[K, V], now time.Time) []rangeItem[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key, it := range s.data {
+		if now.After(it.expiredAt) {
+			continue
+		}
+
+		items = append(items, rangeItem[K, V]{
+			key:       key,
+			val:       it.val,
+			expiredAt: it.expiredAt,
+		})
+	}
+
+	return items
+}
+
+type rangeItem[K comparable, V any] struct {
+	key       K
+	val       V
+	expiredAt time.Time
+}
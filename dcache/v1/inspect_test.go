@@ -0,0 +1,74 @@
+package dcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Peek(t *testing.T) {
+	cache := NewCache[string, int]().WithCapacity(2)
+
+	cache.Set("key1", 1, time.Hour)
+	cache.Set("key2", 2, 0)
+
+	v, expiredAt, ok := cache.Peek("key1")
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiredAt, time.Second)
+
+	// Peek doesn't promote "key1", so it is evicted.
+	cache.Set("key3", 3, 0)
+
+	_, _, ok = cache.Peek("key1")
+	require.False(t, ok)
+
+	_, expiredAt, ok = cache.GetWithExpiry("key2")
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(weekDuration), expiredAt, time.Second)
+}
+
+func TestCache_Touch(t *testing.T) {
+	cache := NewCache[string, int]()
+
+	cache.Set("key", 1, 50*time.Millisecond)
+	require.True(t, cache.Touch("key", time.Hour))
+	require.False(t, cache.Touch("missing", time.Hour))
+
+	time.Sleep(100 * time.Millisecond)
+	cache.DeleteExpiredCache()
+
+	_, ok := cache.Get("key")
+	require.True(t, ok)
+}
+
+func TestCache_Range(t *testing.T) {
+	cache := NewCache[string, int]()
+
+	for i := 0; i < 100; i++ {
+		cache.Set(fmt.Sprintf("key%d", i), i, 0)
+	}
+	cache.Set("expiring", 0, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	var sum int
+	cache.Range(func(key string, val int, _ time.Time) bool {
+		// Calling back must not deadlock.
+		cache.Set(key, val, 0)
+
+		sum += val
+		return true
+	})
+	require.EqualValues(t, 4950, sum)
+	require.Len(t, cache.Keys(), 100)
+
+	var n int
+	cache.Range(func(string, int, time.Time) bool {
+		n++
+		return n < 10
+	})
+	require.EqualValues(t, 10, n)
+}
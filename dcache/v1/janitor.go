@@ -0,0 +1,62 @@
+package dcache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// janitorIntervalDefault - see WithJanitorInterval().
+	janitorIntervalDefault = time.Minute
+	/*
+		janitorBatchN - is a max number of expired items deleted at once under a shard lock. Lock gets released between
+		batches, so the janitor never blocks a shard for long.
+	*/
+	janitorBatchN = 128
+)
+
+/*
+Running - runs the janitor, which reclaims expired items once in a janitor interval (see WithJanitorInterval()) until
+'ctx' is done. Expired items are looked up in expiration order, so only expired ones are visited. Cache size is
+reported meanwhile if metrics are set, see WithMetrics(). It is intended to be used with dsync.Group:
+
+	group.GoUntilWait("cache_janitor", cache.Running)
+*/
+func (c *cache[K, V]) Running(ctx context.Context) error {
+	var ticker = c.clock.NewTicker(c.janitorInterval)
+	defer ticker.Stop()
+
+	c.metrics.running(
+		ctx,
+		func(context.Context) float64 {
+			return float64(c.usage.n.Load())
+		},
+		func(context.Context) float64 {
+			return float64(c.usage.weight.Load())
+		},
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C():
+			c.deleteExpiredIncrementally(ctx)
+		}
+	}
+}
+
+func (c *cache[K, V]) deleteExpiredIncrementally(ctx context.Context) {
+	var now = c.clock.Now()
+
+	for _, s := range c.shards {
+		for s.deleteExpired(now, janitorBatchN) == janitorBatchN {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+
+	c.loads.deleteExpired(now)
+}
@@ -0,0 +1,53 @@
+package dcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/dsync/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Running(t *testing.T) {
+	cache := NewCache[string, int]().
+		WithShards(4).
+		WithJanitorInterval(10 * time.Millisecond)
+
+	for i := 0; i < janitorBatchN*3; i++ {
+		cache.Set(fmt.Sprintf("expiring%d", i), i, 20*time.Millisecond)
+	}
+	cache.Set("lasting", 1, time.Hour)
+
+	ctx, cancel := dctx.WithTTLCancel(dctx.New())
+
+	var group = dsync.NewOneTimeGroup(ctx)
+	group.GoUntilWait("cache_janitor", cache.Running)
+
+	require.Eventually(t, func() bool { return cache.Len() == 1 }, time.Second, 10*time.Millisecond)
+
+	_, ok := cache.Get("lasting")
+	require.True(t, ok)
+
+	cancel()
+	require.NoError(t, group.Wait())
+}
+
+func TestCache_DeleteExpiredCache(t *testing.T) {
+	cache := NewCache[int, int]()
+
+	for i := 0; i < 100; i++ {
+		var ttl = time.Hour
+		if i%2 == 0 {
+			ttl = time.Millisecond
+		}
+
+		cache.Set(i, i, ttl)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	cache.DeleteExpiredCache()
+
+	require.EqualValues(t, 50, cache.Len())
+}
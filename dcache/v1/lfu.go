@@ -0,0 +1,129 @@
+package dcache
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+/*
+lfu - keeps items in a min-heap by use frequency. Readers only increment item hits, while heap order is restored lazily
+once a victim is looked up: heap top is re-positioned until its frequency is actual. Frequencies never decrease, so
+actual top is the least frequently used item. Adding counts as a use, so a new item is not the first to be evicted.
+*/
+type lfu[K comparable, V any] struct {
+	items lfuItems[K, V]
+	seq   uint64
+}
+
+func newLFU[K comparable, V any]() *lfu[K, V] {
+	return &lfu[K, V]{}
+}
+
+func (l *lfu[K, V]) add(it *item[K, V]) *item[K, V] {
+	l.seq++
+	it.seq = l.seq
+	it.hits.Store(1)
+	it.freq = 1
+
+	heap.Push(&l.items, it)
+
+	return nil
+}
+
+func (l *lfu[K, V]) update(it *item[K, V]) {
+	it.hits.Add(1)
+}
+
+func (l *lfu[K, V]) hit(it *item[K, V]) {
+	it.hits.Add(1)
+}
+
+func (l *lfu[K, V]) miss(uint64) {}
+
+func (l *lfu[K, V]) remove(it *item[K, V]) {
+	heap.Remove(&l.items, it.policyI)
+}
+
+func (l *lfu[K, V]) victim() *item[K, V] {
+	for len(l.items) > 0 {
+		it := l.items[0]
+
+		hits := it.hits.Load()
+		if hits == it.freq {
+			return it
+		}
+
+		it.freq = hits
+		heap.Fix(&l.items, 0)
+	}
+
+	return nil
+}
+
+// walk - walks a sorted copy of items, since heap is ordered partially and its frequencies may be outdated.
+func (l *lfu[K, V]) walk(f func(it *item[K, V]) bool) {
+	type lfuWalked struct {
+		it   *item[K, V]
+		hits uint32
+	}
+
+	var walked = make([]lfuWalked, 0, len(l.items))
+	for _, it := range l.items {
+		walked = append(walked, lfuWalked{it: it, hits: it.hits.Load()})
+	}
+
+	slices.SortFunc(walked, func(a, b lfuWalked) int {
+		if a.hits != b.hits {
+			return cmp.Compare(a.hits, b.hits)
+		}
+
+		return cmp.Compare(a.it.seq, b.it.seq)
+	})
+
+	for _, w := range walked {
+		if !f(w.it) {
+			return
+		}
+	}
+}
+
+type lfuItems[K comparable, V any] []*item[K, V]
+
+var _ heap.Interface = (*lfuItems[int, int])(nil)
+
+func (l lfuItems[K, V]) Len() int { return len(l) }
+
+func (l lfuItems[K, V]) Less(i, j int) bool {
+	if l[i].freq != l[j].freq {
+		return l[i].freq < l[j].freq
+	}
+
+	return l[i].seq < l[j].seq
+}
+
+func (l lfuItems[K, V]) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+	l[i].policyI = i
+	l[j].policyI = j
+}
+
+func (l *lfuItems[K, V]) Push(x any) {
+	it := x.(*item[K, V]) //nolint:errcheck
+	it.policyI = len(*l)
+	*l = append(*l, it)
+}
+
+func (l *lfuItems[K, V]) Pop() any {
+	var (
+		old = *l
+		n   = len(old)
+		it  = old[n-1]
+	)
+
+	old[n-1] = nil
+	it.policyI = -1
+	*l = old[:n-1]
+
+	return it
+}
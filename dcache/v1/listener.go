@@ -0,0 +1,48 @@
+package dcache
+
+// RemovalReason - describes why an item has left the cache. See Listener.
+type RemovalReason uint8
+
+const (
+	// RemovalReasonExpired - item time-to-live has expired.
+	RemovalReasonExpired RemovalReason = iota + 1
+	// RemovalReasonEvicted - item was evicted to fit cache capacity.
+	RemovalReasonEvicted
+	// RemovalReasonInvalidated - item was invalidated explicitly.
+	RemovalReasonInvalidated
+	// RemovalReasonReplaced - item value was overwritten with Cache.Set(). The old value is passed to Listener.
+	RemovalReasonReplaced
+)
+
+// String - returns RemovalReason string representation. If RemovalReason is unknown, "unknown" is returned.
+func (r RemovalReason) String() string {
+	switch r {
+	case RemovalReasonExpired:
+		return "expired"
+
+	case RemovalReasonEvicted:
+		return "evicted"
+
+	case RemovalReasonInvalidated:
+		return "invalidated"
+
+	case RemovalReasonReplaced:
+		return "replaced"
+
+	default:
+		return "unknown"
+	}
+}
+
+/*
+Listener - is notified about each item leaving the cache. It is called by a goroutine that has caused removal, after
+shard lock is released, so it may call the cache back. Expired items are reported once they are actually deleted:
+by Cache.DeleteExpiredCache(), Cache.Running() or Cache.Set().
+*/
+type Listener[K comparable, V any] func(key K, val V, reason RemovalReason)
+
+type removal[K comparable, V any] struct {
+	key    K
+	val    V
+	reason RemovalReason
+}
@@ -0,0 +1,62 @@
+package dcache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_WithEvictionListener(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		removed = make(map[string]RemovalReason)
+		cache   Cache[string, int]
+	)
+
+	cache = NewCache[string, int]().
+		WithCapacity(2).
+		WithEvictionListener(func(key string, val int, reason RemovalReason) {
+			// Calling back must not deadlock.
+			_ = cache.Len()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			removed[key] = reason
+		})
+
+	cache.Set("replaced", 1, 0)
+	cache.Set("replaced", 2, 0)
+	cache.Set("invalidated", 1, 0)
+	cache.InvalidateKey("invalidated")
+	cache.Set("expired", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.DeleteExpiredCache()
+	cache.Set("evicting", 1, 0)
+	cache.Set("evicting_too", 1, 0)
+
+	require.EqualValues(t, map[string]RemovalReason{
+		"replaced":    RemovalReasonEvicted,
+		"invalidated": RemovalReasonInvalidated,
+		"expired":     RemovalReasonExpired,
+	}, removed)
+}
+
+func TestCache_WithEvictionListenerReplaced(t *testing.T) {
+	var vals []int
+
+	cache := NewCache[string, int]().
+		WithEvictionListener(func(key string, val int, reason RemovalReason) {
+			require.EqualValues(t, RemovalReasonReplaced, reason)
+
+			vals = append(vals, val)
+		})
+
+	cache.Set("key", 1, 0)
+	cache.Set("key", 2, 0)
+	cache.Set("key", 3, 0)
+
+	require.EqualValues(t, []int{1, 2}, vals)
+}
@@ -0,0 +1,172 @@
+package dcache
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"sync"
+	"time"
+)
+
+/*
+Loader - loads a value missing in cache. Returned value is cached for returned ttl. See Cache.Set() regarding ttl
+defaults.
+*/
+type Loader[V any] func(ctx context.Context) (V, time.Duration, error)
+
+/*
+GetOrLoad - returns cached value or loads it with 'loader' and caches it. Concurrent misses of the same key share a
+single 'loader' call. 'loader' is called with 'ctx' values, but without its cancellation, so when 'ctx' is done, the
+caller stops waiting with context error, while the load proceeds for the others. Loader errors are cached for an error
+ttl if it is set, see WithErrorTTL(). Loader panic is returned as an error.
+*/
+func (c *cache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V]) (V, error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+
+	var l = c.loads.start(key, c.clock.Now(), func() *load[V] {
+		return c.newLoad(ctx, key, loader)
+	})
+
+	select {
+	case <-ctx.Done():
+		return *new(V), ctx.Err()
+
+	case <-l.done:
+		return l.val, l.err
+	}
+}
+
+// newLoad - starts 'loader' in a separate goroutine and returns its load.
+func (c *cache[K, V]) newLoad(ctx context.Context, key K, loader Loader[V]) *load[V] {
+	var l = &load[V]{
+		done: make(chan struct{}),
+	}
+
+	ctx = dctx.WithoutCancel(ctx)
+
+	go func() {
+		defer close(l.done)
+
+		var (
+			ttl    time.Duration
+			loaded = c.metrics.load()
+		)
+
+		derr.OnPanic(
+			func() {
+				l.val, ttl, l.err = loader(ctx)
+			},
+			func(err error) {
+				l.err = err
+			},
+		)
+
+		loaded(l.err)
+
+		if l.err == nil {
+			c.set(key, l.val, ttl, nil, true)
+		}
+
+		c.loads.finish(key, l, c.clock.Now(), c.errTTL)
+	}()
+
+	return l
+}
+
+/*
+loads - tracks loads in progress and failed loads within error ttl. Failed loads are queued in order they have failed,
+so expired ones are forgotten on each start without scanning all loads.
+*/
+type loads[K comparable, V any] struct {
+	mu     sync.Mutex
+	byKs   map[K]*load[V]
+	failed []failedLoad[K, V]
+}
+
+type failedLoad[K comparable, V any] struct {
+	key K
+	ld  *load[V]
+}
+
+func newLoads[K comparable, V any]() *loads[K, V] {
+	return &loads[K, V]{
+		byKs: make(map[K]*load[V]),
+	}
+}
+
+// start - returns 'key' load in progress or failed load within error ttl. Otherwise, starts a new one with 'f'.
+func (l *loads[K, V]) start(key K, now time.Time, f func() *load[V]) *load[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deleteExpiredLocked(now)
+
+	ld, ok := l.byKs[key]
+	if ok && (ld.expiredAt.IsZero() || now.Before(ld.expiredAt)) {
+		return ld
+	}
+
+	ld = f()
+	l.byKs[key] = ld
+
+	return ld
+}
+
+// finish - forgets 'ld' unless it has failed and 'errTTL' > 0.
+func (l *loads[K, V]) finish(key K, ld *load[V], now time.Time, errTTL time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ld.err != nil && errTTL > 0 {
+		ld.expiredAt = now.Add(errTTL)
+		l.failed = append(l.failed, failedLoad[K, V]{key: key, ld: ld})
+
+		return
+	}
+
+	delete(l.byKs, key)
+}
+
+// deleteExpired - forgets failed loads which error ttl has expired.
+func (l *loads[K, V]) deleteExpired(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deleteExpiredLocked(now)
+}
+
+/*
+deleteExpiredLocked - forgets failed loads which error ttl has expired in order they have failed. Error ttl is the same
+for all loads, so it stops at the first failed load which is not expired yet.
+*/
+func (l *loads[K, V]) deleteExpiredLocked(now time.Time) {
+	var n int
+	for ; n < len(l.failed); n++ {
+		var f = l.failed[n]
+		if now.Before(f.ld.expiredAt) {
+			break
+		}
+
+		// Key may have been loaded again since then.
+		if l.byKs[f.key] == f.ld {
+			delete(l.byKs, f.key)
+		}
+	}
+
+	if n == 0 {
+		return
+	}
+
+	clear(l.failed[:n])
+	l.failed = l.failed[n:]
+}
+
+type load[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	// expiredAt - is set once load has failed and its error is cached. Guarded by loads mutex.
+	expiredAt time.Time
+}
@@ -0,0 +1,141 @@
+package dcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	const waitersN = 50
+
+	var (
+		cache   = NewCache[string, int]()
+		calls   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	loader := func(context.Context) (int, time.Duration, error) {
+		calls.Add(1)
+		<-release
+
+		return 7, 0, nil
+	}
+
+	wg.Add(waitersN)
+	for i := 0; i < waitersN; i++ {
+		go func() {
+			defer wg.Done()
+
+			v, err := cache.GetOrLoad(context.Background(), "key", loader)
+			require.NoError(t, err)
+			require.EqualValues(t, 7, v)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.EqualValues(t, 1, calls.Load())
+
+	v, ok := cache.Get("key")
+	require.True(t, ok)
+	require.EqualValues(t, 7, v)
+}
+
+func TestCache_GetOrLoadCanceled(t *testing.T) {
+	var (
+		cache   = NewCache[string, int]()
+		release = make(chan struct{})
+	)
+
+	loader := func(ctx context.Context) (int, time.Duration, error) {
+		<-release
+
+		return 1, 0, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var done = make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(ctx, "key", loader)
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	go close(release)
+
+	v, err := cache.GetOrLoad(context.Background(), "key", loader)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, v)
+}
+
+func TestCache_GetOrLoadErrorTTL(t *testing.T) {
+	var (
+		cache   = NewCache[string, int]().WithErrorTTL(100 * time.Millisecond)
+		calls   atomic.Int32
+		errLoad = errors.New("load")
+	)
+
+	loader := func(context.Context) (int, time.Duration, error) {
+		calls.Add(1)
+
+		return 0, 0, errLoad
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetOrLoad(context.Background(), "key", loader)
+		require.ErrorIs(t, err, errLoad)
+	}
+	require.EqualValues(t, 1, calls.Load())
+
+	time.Sleep(150 * time.Millisecond)
+
+	_, err := cache.GetOrLoad(context.Background(), "key", loader)
+	require.ErrorIs(t, err, errLoad)
+	require.EqualValues(t, 2, calls.Load())
+
+	_, err = NewCache[string, int]().GetOrLoad(context.Background(), "key", func(context.Context) (int, time.Duration, error) {
+		panic("loading")
+	})
+	require.Error(t, err)
+}
+
+func TestCache_GetOrLoadErrorTTLExpired(t *testing.T) {
+	var (
+		clock   = dtime.NewFakeClock(time.Now())
+		c       = NewCache[int, int]().WithCapacity(10).WithErrorTTL(time.Millisecond).WithClock(clock)
+		errLoad = errors.New("load")
+	)
+
+	loader := func(context.Context) (int, time.Duration, error) {
+		return 0, 0, errLoad
+	}
+
+	for i := 0; i < 1000; i++ {
+		_, err := c.GetOrLoad(context.Background(), i, loader)
+		require.ErrorIs(t, err, errLoad)
+
+		clock.Advance(time.Millisecond)
+	}
+
+	var loads = c.(*cache[int, int]).loads
+
+	loads.mu.Lock()
+	defer loads.mu.Unlock()
+
+	require.LessOrEqual(t, len(loads.byKs), 1)
+	require.LessOrEqual(t, len(loads.failed), 1)
+}
@@ -0,0 +1,117 @@
+package dcache
+
+import (
+	"context"
+	"time"
+)
+
+// MemoizeOption - configures a cache created by Memoize().
+type MemoizeOption func(o *memoizeOptions)
+
+// OptionMemoizeWithTTL - see WithDuration().
+func OptionMemoizeWithTTL(ttl time.Duration) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.ttl = ttl
+	}
+}
+
+// OptionMemoizeWithCapacity - see WithCapacity().
+func OptionMemoizeWithCapacity(capacity int) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.capacity = capacity
+	}
+}
+
+// OptionMemoizeWithErrorTTL - caches errors for 'ttl'. See WithErrorTTL().
+func OptionMemoizeWithErrorTTL(ttl time.Duration) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.errTTL = ttl
+	}
+}
+
+/*
+Memoize - returns 'fn' caching its results by key in a new Cache configured with 'options'. Concurrent calls with the
+same key share a single 'fn' call, see Cache.GetOrLoad(). Errors are not cached unless OptionMemoizeWithErrorTTL() is
+passed. Use Key2 or Key3 as a key to memoize a function of several arguments. Expired results are reclaimed on cache
+writes only, so use MemoizeCache() with a running cache to reclaim them periodically, see Cache.Running().
+*/
+func Memoize[K comparable, V any](
+	fn func(ctx context.Context, key K) (V, error),
+	options ...MemoizeOption,
+) func(ctx context.Context, key K) (V, error) {
+	var o = memoizeOptions{
+		capacity: -1,
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	var cache = NewCache[K, V]().
+		WithCapacity(o.capacity).
+		WithErrorTTL(o.errTTL)
+
+	if o.ttl > 0 {
+		cache = cache.WithDuration(o.ttl)
+	}
+
+	return MemoizeCache(cache, fn)
+}
+
+// MemoizeCache - is the same as Memoize(), but results are cached in 'cache' for its default ttl.
+func MemoizeCache[K comparable, V any](
+	cache Cache[K, V],
+	fn func(ctx context.Context, key K) (V, error),
+) func(ctx context.Context, key K) (V, error) {
+	return func(ctx context.Context, key K) (V, error) {
+		return cache.GetOrLoad(ctx, key, func(ctx context.Context) (V, time.Duration, error) {
+			val, err := fn(ctx, key)
+			return val, 0, err
+		})
+	}
+}
+
+// Key2 - is a comparable key made of two arguments. See Memoize().
+type Key2[A, B comparable] struct {
+	A A
+	B B
+}
+
+func NewKey2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{A: a, B: b}
+}
+
+// cacheHasher - see keyHasher.
+func (*Key2[A, B]) cacheHasher() any {
+	var a, b = hasherOf[A](), hasherOf[B]()
+
+	return func(k Key2[A, B]) uint64 {
+		return hashCombine(a(k.A), b(k.B))
+	}
+}
+
+// Key3 - is a comparable key made of three arguments. See Memoize().
+type Key3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
+
+func NewKey3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{A: a, B: b, C: c}
+}
+
+// cacheHasher - see keyHasher.
+func (*Key3[A, B, C]) cacheHasher() any {
+	var a, b, c = hasherOf[A](), hasherOf[B](), hasherOf[C]()
+
+	return func(k Key3[A, B, C]) uint64 {
+		return hashCombine(hashCombine(a(k.A), b(k.B)), c(k.C))
+	}
+}
+
+type memoizeOptions struct {
+	ttl      time.Duration
+	errTTL   time.Duration
+	capacity int
+}
@@ -0,0 +1,58 @@
+package dcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoize(t *testing.T) {
+	var calls atomic.Int32
+
+	rate := Memoize(
+		func(ctx context.Context, key Key2[string, string]) (float64, error) {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+
+			if key.A == key.B {
+				return 0, errors.New("same currencies")
+			}
+
+			return 2, nil
+		},
+		OptionMemoizeWithTTL(50*time.Millisecond),
+		OptionMemoizeWithCapacity(10),
+		OptionMemoizeWithErrorTTL(time.Hour),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := rate(context.Background(), NewKey2("USD", "EUR"))
+			require.NoError(t, err)
+			require.EqualValues(t, 2, v)
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 1, calls.Load())
+
+	for i := 0; i < 2; i++ {
+		_, err := rate(context.Background(), NewKey2("USD", "USD"))
+		require.Error(t, err)
+	}
+	require.EqualValues(t, 2, calls.Load())
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := rate(context.Background(), NewKey2("USD", "EUR"))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, calls.Load())
+}
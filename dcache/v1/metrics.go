@@ -0,0 +1,108 @@
+package dcache
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dmetrics/dprom/v1"
+	"time"
+)
+
+// metricsInterval - is how often Cache.Running() reports cache size. See WithMetrics().
+const metricsInterval = 15 * time.Second
+
+const (
+	metricsMethodGet    = "get"
+	metricsMethodSet    = "set"
+	metricsMethodLoad   = "load"
+	metricsMethodRemove = "remove"
+	metricsMethodLen    = "len"
+	metricsMethodWeight = "weight"
+
+	metricsResultHit   = "hit"
+	metricsResultMiss  = "miss"
+	metricsResultOK    = "ok"
+	metricsResultError = "error"
+)
+
+// metrics - reports cache usage with entry labels. See WithMetrics().
+type metrics struct {
+	entry   dprom.Entry
+	hits    dprom.Counter
+	misses  dprom.Counter
+	sets    dprom.Counter
+	removes [RemovalReasonReplaced + 1]dprom.Counter
+}
+
+func newMetrics(entry dprom.Entry) *metrics {
+	var m = &metrics{
+		entry:  entry,
+		hits:   entry.WithMethod(metricsMethodGet).WithResult(metricsResultHit).Counter(),
+		misses: entry.WithMethod(metricsMethodGet).WithResult(metricsResultMiss).Counter(),
+		sets:   entry.WithMethod(metricsMethodSet).Counter(),
+	}
+
+	for r := RemovalReasonExpired; r <= RemovalReasonReplaced; r++ {
+		m.removes[r] = entry.WithMethod(metricsMethodRemove).WithResult(r.String()).Counter()
+	}
+
+	return m
+}
+
+func (m *metrics) get(hit bool) {
+	if m == nil {
+		return
+	}
+
+	if hit {
+		m.hits.Inc()
+		return
+	}
+
+	m.misses.Inc()
+}
+
+func (m *metrics) set() {
+	if m == nil {
+		return
+	}
+
+	m.sets.Inc()
+}
+
+func (m *metrics) removed(reason RemovalReason) {
+	if m == nil || int(reason) >= len(m.removes) {
+		return
+	}
+
+	m.removes[reason].Inc()
+}
+
+// load - starts measuring a load. Returned function must be called once the load is done.
+func (m *metrics) load() func(err error) {
+	if m == nil {
+		return func(error) {}
+	}
+
+	var histogram = m.entry.WithMethod(metricsMethodLoad).HistogramMs()
+
+	return func(err error) {
+		if err != nil {
+			histogram.WriteResult(metricsResultError)
+			return
+		}
+
+		histogram.WriteResult(metricsResultOK)
+	}
+}
+
+/*
+running - reports cache size with 'length' and 'weight' once in a metrics interval until 'ctx' is done. It returns
+at once.
+*/
+func (m *metrics) running(ctx context.Context, length, weight func(ctx context.Context) float64) {
+	if m == nil {
+		return
+	}
+
+	go m.entry.WithMethod(metricsMethodLen).Gauge().SettingF(ctx, length, metricsInterval)
+	go m.entry.WithMethod(metricsMethodWeight).Gauge().SettingF(ctx, weight, metricsInterval)
+}
@@ -0,0 +1,49 @@
+package dcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/don-nv/go-dpkg/dmetrics/dprom/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsTestRuns - makes entities unique per test run, since metrics are registered globally, e.g. for -count=2.
+var metricsTestRuns atomic.Int32
+
+func TestCache_WithMetrics(t *testing.T) {
+	var entity = fmt.Sprintf("test_cache_%d", metricsTestRuns.Add(1))
+
+	cache := NewCache[string, int]().
+		WithCapacity(1).
+		WithMetrics(dprom.NewEntry.WithEntity(entity))
+
+	cache.Set("key1", 1, 0)
+	cache.Set("key2", 2, 0)
+	cache.Get("key1")
+	cache.Get("key2")
+
+	_, err := cache.GetOrLoad(context.Background(), "key3", func(context.Context) (int, time.Duration, error) {
+		return 0, 0, errors.New("load")
+	})
+	require.Error(t, err)
+
+	var w = httptest.NewRecorder()
+	dprom.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	var body = w.Body.String()
+	for _, line := range []string{
+		`counter_dprom{entity="%[1]s",entity_group="",method="get",method_group="",result="hit"} 1`,
+		`counter_dprom{entity="%[1]s",entity_group="",method="get",method_group="",result="miss"} 2`,
+		`counter_dprom{entity="%[1]s",entity_group="",method="set",method_group="",result=""} 2`,
+		`counter_dprom{entity="%[1]s",entity_group="",method="remove",method_group="",result="evicted"} 1`,
+		`histogram_ms_dprom_count{entity="%[1]s",entity_group="",method="load",method_group="",result="error"} 1`,
+	} {
+		require.Contains(t, body, fmt.Sprintf(line, entity))
+	}
+}
@@ -0,0 +1,160 @@
+package dcache
+
+import (
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/don-nv/go-dpkg/dmetrics/dprom/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"time"
+)
+
+// options - must be applied right after NewCache() and before cache usage. Otherwise, cached items may be dropped.
+type options[K comparable, V any] interface {
+	WithCapacity(capacity int) Cache[K, V]
+	WithDuration(ttl time.Duration) Cache[K, V]
+	WithShards(n int) Cache[K, V]
+	WithErrorTTL(ttl time.Duration) Cache[K, V]
+	WithJanitorInterval(d time.Duration) Cache[K, V]
+	WithEvictionListener(f Listener[K, V]) Cache[K, V]
+	WithPolicy(p Policy) Cache[K, V]
+	WithMaxWeight(weight int64, weigher func(key K, val V) int64) Cache[K, V]
+	WithMetrics(entry dprom.Entry) Cache[K, V]
+	WithCodec(codec Codec) Cache[K, V]
+	WithRefresh(softTTL time.Duration, loader KeyLoader[K, V]) Cache[K, V]
+	WithLogger(log dlog.Logger) Cache[K, V]
+	WithClock(clock dtime.Clock) Cache[K, V]
+	WithHasher(f func(key K) uint64) Cache[K, V]
+}
+
+// WithCapacity - limits items number across all shards. Negative 'capacity' means no limit.
+func (c *cache[K, V]) WithCapacity(capacity int) Cache[K, V] {
+	c.capacity = capacity
+	c.resetShards()
+
+	return c
+}
+
+func (c *cache[K, V]) WithDuration(ttl time.Duration) Cache[K, V] {
+	c.ttl = ttl
+	return c
+}
+
+/*
+WithShards - sets number of independently locked shards. More shards reduce lock contention, but make eviction order
+less strict: a victim is looked up within a shard only. 'n' < 1 is considered to be 1.
+*/
+func (c *cache[K, V]) WithShards(n int) Cache[K, V] {
+	c.shardsN = max(1, n)
+	c.resetShards()
+
+	return c
+}
+
+// WithErrorTTL - caches Cache.GetOrLoad() loader errors for 'ttl'. Errors are not cached if 'ttl' < 1 (default).
+func (c *cache[K, V]) WithErrorTTL(ttl time.Duration) Cache[K, V] {
+	c.errTTL = ttl
+	return c
+}
+
+// WithJanitorInterval - sets how often Cache.Running() reclaims expired items. 'd' < 1 is ignored.
+func (c *cache[K, V]) WithJanitorInterval(d time.Duration) Cache[K, V] {
+	if d > 0 {
+		c.janitorInterval = d
+	}
+
+	return c
+}
+
+// WithEvictionListener - sets 'f' to be notified about each item leaving the cache. See Listener.
+func (c *cache[K, V]) WithEvictionListener(f Listener[K, V]) Cache[K, V] {
+	c.listener = f
+	c.resetShardsListener()
+
+	return c
+}
+
+// WithPolicy - sets eviction policy. PolicyLRU is used by default.
+func (c *cache[K, V]) WithPolicy(p Policy) Cache[K, V] {
+	c.policy = p
+	c.resetShards()
+
+	return c
+}
+
+/*
+WithMaxWeight - limits total weight of items across all shards, e.g. their size in bytes. Each item weight is measured
+by 'weigher' once it is set. Items are evicted by policy until the total weight fits 'weight', while an item heavier
+than 'weight' is not cached at all. 'weight' < 1 means no limit. Can be combined with WithCapacity().
+*/
+func (c *cache[K, V]) WithMaxWeight(weight int64, weigher func(key K, val V) int64) Cache[K, V] {
+	c.maxWeight = weight
+	c.weigher = weigher
+
+	return c
+}
+
+/*
+WithMetrics - reports cache usage as dprom metrics labeled with 'entry', e.g. dprom.NewEntry.WithEntity("users"). Method
+label is set to one of the following:
+  - "get" - counter of Cache.Get() calls with "hit" or "miss" result;
+  - "set" - counter of Cache.Set() calls;
+  - "remove" - counter of removed items with RemovalReason result;
+  - "load" - histogram of Cache.GetOrLoad() loader calls with "ok" or "error" result;
+  - "len", "weight" - gauges of Cache.Len() and Cache.Weight(), which are set while Cache.Running().
+*/
+func (c *cache[K, V]) WithMetrics(entry dprom.Entry) Cache[K, V] {
+	c.metrics = newMetrics(entry)
+	c.resetShardsListener()
+
+	return c
+}
+
+// WithCodec - sets Cache.Snapshot() and Cache.Restore() codec. CodecGob is used by default.
+func (c *cache[K, V]) WithCodec(codec Codec) Cache[K, V] {
+	c.codec = codec
+	return c
+}
+
+/*
+WithRefresh - enables stale-while-revalidate mode. Items older than 'softTTL' are still returned, while a single
+background refresh of such an item is started with 'loader'. Items are dropped only once their ttl passed to
+Cache.Set() expires, so it is a hard one. 'loader' is called with a key only, see KeyLoader. Refresh failures are
+logged, see WithLogger(), while a stale item is kept and refresh is retried on the next access. Use WithErrorTTL() to
+delay retries. 'softTTL' < 1 disables refresh.
+*/
+func (c *cache[K, V]) WithRefresh(softTTL time.Duration, loader KeyLoader[K, V]) Cache[K, V] {
+	c.softTTL = softTTL
+	c.refresh = loader
+
+	return c
+}
+
+// WithLogger - sets a logger for background operations failures. Logger named "dcache" is used by default.
+func (c *cache[K, V]) WithLogger(log dlog.Logger) Cache[K, V] {
+	c.log = log
+	return c
+}
+
+/*
+WithClock - sets a clock items expiration, refresh and janitor interval are measured by, e.g. dtime.FakeClock in tests.
+dtime.ClockReal is used by default.
+*/
+func (c *cache[K, V]) WithClock(clock dtime.Clock) Cache[K, V] {
+	c.clock = clock
+	return c
+}
+
+/*
+WithHasher - replaces default keys hashing used to spread keys over shards, e.g. to avoid reflection hashing struct
+keys, see hasherOf(). Keys equal by == must have the same hash. Nil 'f' restores default hashing. Existing items are
+dropped, the same as by WithShards().
+*/
+func (c *cache[K, V]) WithHasher(f func(key K) uint64) Cache[K, V] {
+	if f == nil {
+		f = hasherOf[K]()
+	}
+
+	c.hasher = f
+	c.resetShards()
+
+	return c
+}
@@ -0,0 +1,214 @@
+package dcache
+
+import "container/list"
+
+// Policy - selects items to be evicted once cache exceeds its capacity. See WithPolicy().
+type Policy uint8
+
+const (
+	// PolicyLRU - evicts the least recently used item. It is a default policy.
+	PolicyLRU Policy = iota
+	// PolicyFIFO - evicts the oldest added item despite item accesses.
+	PolicyFIFO
+	// PolicyLFU - evicts the least frequently used item. The oldest one is evicted among equally used items.
+	PolicyLFU
+	/*
+		PolicyTinyLFU - is a W-TinyLFU: new items get into a small LRU window and are admitted to the main segmented LRU
+		only if they are estimated to be used more frequently than the main victim. It keeps frequently used items
+		under scans, that is when many items are accessed once.
+	*/
+	PolicyTinyLFU
+)
+
+// String - returns Policy string representation. If Policy is unknown, "unknown" is returned.
+func (p Policy) String() string {
+	switch p {
+	case PolicyLRU:
+		return "lru"
+
+	case PolicyFIFO:
+		return "fifo"
+
+	case PolicyLFU:
+		return "lfu"
+
+	case PolicyTinyLFU:
+		return "tiny_lfu"
+
+	default:
+		return "unknown"
+	}
+}
+
+/*
+policy - orders shard items for eviction. All methods, but hit() and miss(), are called under shard write lock.
+*/
+type policy[K comparable, V any] interface {
+	// add - adds a new item. Returned item, if any, is rejected at once and must be removed. It may be 'it' itself.
+	add(it *item[K, V]) *item[K, V]
+	// update - is called once item value is replaced.
+	update(it *item[K, V])
+	// hit - is called under shard read lock concurrently, so it only marks 'it' to be reordered later.
+	hit(it *item[K, V])
+	// miss - is called under shard read lock concurrently once an item with hash 'h' is not found.
+	miss(h uint64)
+	remove(it *item[K, V])
+	// victim - returns an item to be evicted or nil if there are no items.
+	victim() *item[K, V]
+	/*
+		walk - calls 'f' for each item from the first to be evicted to the last one, until 'f' returns false. It is
+		called under shard read lock, so it never reorders items.
+	*/
+	walk(f func(it *item[K, V]) bool)
+}
+
+/*
+newPolicy - returns a new 'p' policy for a shard. 'capacity' is an expected shard capacity, which is negative if
+cache is not limited. Unknown 'p' is considered to be PolicyLRU.
+*/
+func newPolicy[K comparable, V any](p Policy, capacity int) policy[K, V] {
+	switch p {
+	case PolicyFIFO:
+		return newFIFO[K, V]()
+
+	case PolicyLFU:
+		return newLFU[K, V]()
+
+	case PolicyTinyLFU:
+		return newTinyLFU[K, V](capacity)
+
+	default:
+		return newLRU[K, V]()
+	}
+}
+
+/*
+lru - readers only mark items as accessed, so they never wait for a write lock. Accessed items get promoted once they
+are considered for eviction and the next item is considered instead. So, LRU order is restored lazily in a batch.
+*/
+type lru[K comparable, V any] struct {
+	items *list.List
+}
+
+func newLRU[K comparable, V any]() *lru[K, V] {
+	return &lru[K, V]{
+		items: list.New(),
+	}
+}
+
+func (l *lru[K, V]) add(it *item[K, V]) *item[K, V] {
+	it.el = l.items.PushFront(it)
+
+	return nil
+}
+
+func (l *lru[K, V]) update(it *item[K, V]) {
+	it.accessed.Store(false)
+	l.items.MoveToFront(it.el)
+}
+
+func (l *lru[K, V]) hit(it *item[K, V]) {
+	if !it.accessed.Load() {
+		it.accessed.Store(true)
+	}
+}
+
+func (l *lru[K, V]) miss(uint64) {}
+
+func (l *lru[K, V]) remove(it *item[K, V]) {
+	l.items.Remove(it.el)
+}
+
+func (l *lru[K, V]) victim() *item[K, V] {
+	return listVictim[K, V](l.items)
+}
+
+func (l *lru[K, V]) walk(f func(it *item[K, V]) bool) {
+	listWalk[K, V](l.items, f)
+}
+
+// listVictim - returns the least recently used item of 'items' promoting accessed ones. See lru.
+func listVictim[K comparable, V any](items *list.List) *item[K, V] {
+	for el := items.Back(); el != nil; el = items.Back() {
+		it := el.Value.(*item[K, V]) //nolint:errcheck
+		if !it.accessed.Load() {
+			return it
+		}
+
+		it.accessed.Store(false)
+		items.MoveToFront(el)
+	}
+
+	return nil
+}
+
+/*
+listWalk - walks 'items' in the order they would be evicted by listVictim(): not accessed items go first, then
+accessed ones, which would be promoted. Returns false if 'f' has stopped walking.
+*/
+func listWalk[K comparable, V any](items *list.List, f func(it *item[K, V]) bool) bool {
+	var accessed []*item[K, V]
+
+	for el := items.Back(); el != nil; el = el.Prev() {
+		it := el.Value.(*item[K, V]) //nolint:errcheck
+		if it.accessed.Load() {
+			accessed = append(accessed, it)
+			continue
+		}
+
+		if !f(it) {
+			return false
+		}
+	}
+
+	for _, it := range accessed {
+		if !f(it) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type fifo[K comparable, V any] struct {
+	items *list.List
+}
+
+func newFIFO[K comparable, V any]() *fifo[K, V] {
+	return &fifo[K, V]{
+		items: list.New(),
+	}
+}
+
+func (f *fifo[K, V]) add(it *item[K, V]) *item[K, V] {
+	it.el = f.items.PushFront(it)
+
+	return nil
+}
+
+func (f *fifo[K, V]) update(*item[K, V]) {}
+
+func (f *fifo[K, V]) hit(*item[K, V]) {}
+
+func (f *fifo[K, V]) miss(uint64) {}
+
+func (f *fifo[K, V]) remove(it *item[K, V]) {
+	f.items.Remove(it.el)
+}
+
+func (f *fifo[K, V]) walk(fn func(it *item[K, V]) bool) {
+	for el := f.items.Back(); el != nil; el = el.Prev() {
+		if !fn(el.Value.(*item[K, V])) { //nolint:errcheck
+			return
+		}
+	}
+}
+
+func (f *fifo[K, V]) victim() *item[K, V] {
+	el := f.items.Back()
+	if el == nil {
+		return nil
+	}
+
+	return el.Value.(*item[K, V]) //nolint:errcheck
+}
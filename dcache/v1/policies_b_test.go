@@ -0,0 +1,92 @@
+package dcache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const (
+	traceKeysN     = 100_000
+	traceLen       = 1 << 20
+	traceCapacity  = 2_000
+	traceZipfS     = 1.1
+	traceScanEvery = 10_000
+	traceScanLen   = traceCapacity * 2
+)
+
+var policiesToTrace = []Policy{PolicyLRU, PolicyFIFO, PolicyLFU, PolicyTinyLFU}
+
+// BenchmarkPolicy_HitRatioZipf - reports hit ratio on a Zipf distributed keys trace.
+func BenchmarkPolicy_HitRatioZipf(b *testing.B) {
+	benchHitRatio(b, newZipfTrace(traceLen))
+}
+
+/*
+BenchmarkPolicy_HitRatioScan - reports hit ratio on a Zipf distributed keys trace interrupted by scans of keys accessed
+once.
+*/
+func BenchmarkPolicy_HitRatioScan(b *testing.B) {
+	benchHitRatio(b, newScanTrace(traceLen))
+}
+
+func benchHitRatio(b *testing.B, trace []int) {
+	for _, p := range policiesToTrace {
+		b.Run(p.String(), func(b *testing.B) {
+			var (
+				cache = NewCache[int, int]().WithPolicy(p).WithCapacity(traceCapacity)
+				hits  int
+			)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var key = trace[i%len(trace)]
+
+				if _, ok := cache.Get(key); ok {
+					hits++
+
+					continue
+				}
+
+				cache.Set(key, key, 0)
+			}
+
+			b.ReportMetric(float64(hits)/float64(b.N)*100, "hit%")
+		})
+	}
+}
+
+func newZipfTrace(n int) []int {
+	var (
+		zipf  = rand.NewZipf(rand.New(rand.NewSource(1)), traceZipfS, 1, traceKeysN-1) //nolint:gosec
+		trace = make([]int, 0, n)
+	)
+
+	for len(trace) < n {
+		trace = append(trace, int(zipf.Uint64()))
+	}
+
+	return trace
+}
+
+// newScanTrace - scanned keys are never repeated and don't intersect with Zipf ones.
+func newScanTrace(n int) []int {
+	var (
+		zipf     = newZipfTrace(n)
+		trace    = make([]int, 0, n)
+		scanNext = traceKeysN
+	)
+
+	for i := 0; len(trace) < n; i++ {
+		if i > 0 && i%traceScanEvery == 0 {
+			for j := 0; j < traceScanLen && len(trace) < n; j++ {
+				trace = append(trace, scanNext)
+				scanNext++
+			}
+		}
+
+		trace = append(trace, zipf[i%len(zipf)])
+	}
+
+	return trace
+}
@@ -0,0 +1,73 @@
+package dcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_WithPolicyFIFO(t *testing.T) {
+	cache := NewCache[string, int]().
+		WithPolicy(PolicyFIFO).
+		WithCapacity(2)
+
+	cache.Set("key1", 1, 0)
+	cache.Set("key2", 2, 0)
+	_, _ = cache.Get("key1")
+	cache.Set("key3", 3, 0)
+
+	_, ok := cache.Get("key1")
+	require.False(t, ok)
+	_, ok = cache.Get("key2")
+	require.True(t, ok)
+}
+
+func TestCache_WithPolicyLFU(t *testing.T) {
+	cache := NewCache[string, int]().
+		WithPolicy(PolicyLFU).
+		WithCapacity(2)
+
+	cache.Set("key1", 1, 0)
+	cache.Set("key2", 2, 0)
+	_, _ = cache.Get("key1")
+	cache.Set("key3", 3, 0)
+
+	_, ok := cache.Get("key2")
+	require.False(t, ok)
+	_, ok = cache.Get("key1")
+	require.True(t, ok)
+}
+
+func TestCache_WithPolicyTinyLFU(t *testing.T) {
+	const (
+		capacity = 100
+		scanN    = capacity * 10
+	)
+
+	cache := NewCache[int, int]().
+		WithPolicy(PolicyTinyLFU).
+		WithCapacity(capacity)
+
+	// Hot items.
+	for j := 0; j < 5; j++ {
+		for i := 0; i < capacity/2; i++ {
+			if _, ok := cache.Get(i); !ok {
+				cache.Set(i, i, 0)
+			}
+		}
+	}
+
+	for i := capacity; i < capacity+scanN; i++ {
+		cache.Set(i, i, 0)
+	}
+
+	require.LessOrEqual(t, cache.Len(), capacity)
+
+	var hotN int
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := cache.Get(i); ok {
+			hotN++
+		}
+	}
+	require.Greater(t, hotN, capacity/4)
+}
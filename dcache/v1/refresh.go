@@ -0,0 +1,33 @@
+package dcache
+
+import (
+	"context"
+	"time"
+)
+
+// KeyLoader - is the same as Loader, but is provided with a key to be loaded. See WithRefresh().
+type KeyLoader[K comparable, V any] func(ctx context.Context, key K) (V, time.Duration, error)
+
+/*
+refreshAhead - starts 'key' refresh unless it is loaded already, e.g. by Cache.GetOrLoad() or a previous refresh.
+Failure of the started refresh is logged.
+*/
+func (c *cache[K, V]) refreshAhead(key K, now time.Time) {
+	var ctx = context.Background()
+
+	c.loads.start(key, now, func() *load[V] {
+		l := c.newLoad(ctx, key, func(ctx context.Context) (V, time.Duration, error) {
+			return c.refresh(ctx, key)
+		})
+
+		go func() {
+			<-l.done
+
+			if l.err != nil {
+				c.log.E().Scope(ctx).Any("key", key).Writef("refreshing stale item: %s", l.err)
+			}
+		}()
+
+		return l
+	})
+}
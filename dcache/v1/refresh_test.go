@@ -0,0 +1,92 @@
+package dcache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_WithRefresh(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		fail    atomic.Bool
+		release = make(chan struct{})
+	)
+
+	cache := NewCache[string, int]().
+		WithRefresh(50*time.Millisecond, func(ctx context.Context, key string) (int, time.Duration, error) {
+			<-release
+
+			if fail.Load() {
+				return 0, 0, errors.New("refresh")
+			}
+
+			return int(calls.Add(1)) + 1, 200 * time.Millisecond, nil
+		})
+
+	cache.Set("key", 1, 200*time.Millisecond)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// Stale value is returned, while a single refresh is in progress.
+	for i := 0; i < 10; i++ {
+		v, ok := cache.Get("key")
+		require.True(t, ok)
+		require.EqualValues(t, 1, v)
+	}
+
+	close(release)
+	require.Eventually(t, func() bool {
+		v, _ := cache.Get("key")
+		return v == 2
+	}, time.Second, 5*time.Millisecond)
+	require.EqualValues(t, 1, calls.Load())
+
+	// Failed refresh keeps stale value until hard ttl.
+	fail.Store(true)
+	time.Sleep(60 * time.Millisecond)
+
+	v, ok := cache.Get("key")
+	require.True(t, ok)
+	require.EqualValues(t, 2, v)
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, ok = cache.Get("key")
+	require.False(t, ok)
+}
+
+func TestCache_WithRefreshKeepsTags(t *testing.T) {
+	var (
+		clock = dtime.NewFakeClock(time.Now())
+		cache = NewCache[string, int]().
+			WithClock(clock).
+			WithRefresh(time.Minute, func(ctx context.Context, key string) (int, time.Duration, error) {
+				return 2, time.Hour, nil
+			})
+	)
+
+	cache.Set("k", 1, time.Hour, "user:1")
+
+	clock.Advance(2 * time.Minute)
+
+	// Stale value triggers refresh.
+	v, ok := cache.Get("k")
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+
+	require.Eventually(t, func() bool {
+		v, _ := cache.Get("k")
+		return v == 2
+	}, time.Second, time.Millisecond)
+
+	cache.InvalidateTag("user:1")
+
+	_, ok = cache.Get("k")
+	require.False(t, ok)
+}
@@ -0,0 +1,393 @@
+package dcache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"hash/crc32"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// segmentHeaderSize - is a size of a record header: payload size and payload crc32, both are uint32.
+	segmentHeaderSize = 8
+	// segmentCompactSizeMin - segment is not compacted until it reaches this size.
+	segmentCompactSizeMin = 1 << 20
+	// segmentCompactLivePercent - segment is compacted once its live records take less than this share of its size.
+	segmentCompactLivePercent = 50
+)
+
+// errSegmentTorn - is returned once a record is partially written, e.g. on crash.
+var errSegmentTorn = errors.New("segment record is torn")
+
+/*
+segment - is a file-backed storage of a tiered cache L2. It is an append-only file of records, so the last record of a
+key wins, while the previous ones become dead. Invalidated keys get a deleting record. Index of live records is kept in
+memory and is rebuilt once the file is opened. Dead and expired records are dropped by compaction, which rewrites live
+ones into a new file.
+*/
+type segment[K comparable, V any] struct {
+	mu    sync.RWMutex
+	path  string
+	file  *os.File
+	codec Codec
+	clock dtime.Clock
+	index map[K]segmentEntry
+	// size - is the file size.
+	size int64
+	// live - is a size of live records.
+	live int64
+}
+
+type segmentEntry struct {
+	offset    int64
+	size      int64
+	expiredAt time.Time
+	tags      []string
+}
+
+// segmentRecord - is a record payload encoded with a codec. Fields are exported to be encoded.
+type segmentRecord[K comparable, V any] struct {
+	Key       K
+	Val       V
+	ExpiredAt time.Time
+	Tags      []string
+	Deleted   bool
+}
+
+/*
+openSegment - opens or creates a segment file at 'path' and rebuilds its index. A torn tail, e.g. left by a crash, is
+truncated, while a record not decoded with 'codec' fails opening.
+*/
+func openSegment[K comparable, V any](path string, codec Codec) (*segment[K, V], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+
+	s := &segment[K, V]{
+		path:  path,
+		file:  file,
+		codec: codec,
+		clock: dtime.ClockReal,
+		index: make(map[K]segmentEntry),
+	}
+
+	err = s.load(s.clock.Now())
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("loading index: %w", err)
+	}
+
+	return s, nil
+}
+
+// load - rebuilds index reading records sequentially. Records following a torn one are dropped.
+func (s *segment[K, V]) load(now time.Time) error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("getting file info: %w", err)
+	}
+
+	var (
+		reader = bufio.NewReader(s.file)
+		offset int64
+	)
+
+	for {
+		rec, size, err := s.readRecord(reader, info.Size()-offset)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errSegmentTorn) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		s.apply(rec, segmentEntry{offset: offset, size: size}, now)
+		offset += size
+	}
+
+	err = s.file.Truncate(offset)
+	if err != nil {
+		return fmt.Errorf("truncating torn tail: %w", err)
+	}
+
+	s.size = offset
+
+	return nil
+}
+
+/*
+get - returns 'key' record if it is live and is not expired. Returned error is related to file reading or record
+decoding.
+*/
+func (s *segment[K, V]) get(key K, now time.Time) (segmentRecord[K, V], bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.index[key]
+	if !ok || !now.Before(entry.expiredAt) {
+		return segmentRecord[K, V]{}, false, nil
+	}
+
+	rec, _, err := s.readRecord(io.NewSectionReader(s.file, entry.offset, entry.size), entry.size)
+	if err != nil {
+		return segmentRecord[K, V]{}, false, fmt.Errorf("reading record: %w", err)
+	}
+
+	return rec, true, nil
+}
+
+func (s *segment[K, V]) set(key K, val V, expiredAt time.Time, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(segmentRecord[K, V]{
+		Key:       key,
+		Val:       val,
+		ExpiredAt: expiredAt,
+		Tags:      tags,
+	})
+}
+
+// deleteFunc - writes deleting records for live keys which entries 'match'.
+func (s *segment[K, V]) deleteFunc(match func(key K, entry segmentEntry) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.index {
+		if !match(key, entry) {
+			continue
+		}
+
+		err := s.write(segmentRecord[K, V]{Key: key, Deleted: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delete - writes deleting record for 'key' if it is live.
+func (s *segment[K, V]) delete(key K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[key]; !ok {
+		return nil
+	}
+
+	return s.write(segmentRecord[K, V]{Key: key, Deleted: true})
+}
+
+func (s *segment[K, V]) deleteTag(tag string) error {
+	return s.deleteFunc(func(_ K, entry segmentEntry) bool {
+		return slices.Contains(entry.tags, tag)
+	})
+}
+
+/*
+deleteExpired - forgets expired records, so they become dead. No deleting records are needed, since expired records
+are skipped once index is rebuilt.
+*/
+func (s *segment[K, V]) deleteExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.index {
+		if !now.Before(entry.expiredAt) {
+			delete(s.index, key)
+			s.live -= entry.size
+		}
+	}
+}
+
+// compactIfNeeded - compacts segment if it is large enough and most of its records are dead.
+func (s *segment[K, V]) compactIfNeeded() error {
+	s.mu.RLock()
+	var needed = s.size >= segmentCompactSizeMin && s.live*100 < s.size*segmentCompactLivePercent
+	s.mu.RUnlock()
+
+	if !needed {
+		return nil
+	}
+
+	return s.compact(s.clock.Now())
+}
+
+/*
+compact - rewrites live not expired records into a new file, which then replaces the segment one. Segment is locked
+meanwhile.
+*/
+func (s *segment[K, V]) compact(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tmpPath = s.path + ".compact"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating compacted file: %w", err)
+	}
+
+	var (
+		index  = make(map[K]segmentEntry, len(s.index))
+		offset int64
+	)
+
+	for key, entry := range s.index {
+		if !now.Before(entry.expiredAt) {
+			continue
+		}
+
+		_, err = io.Copy(tmp, io.NewSectionReader(s.file, entry.offset, entry.size))
+		if err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("copying record: %w", err)
+		}
+
+		entry.offset = offset
+		index[key] = entry
+		offset += entry.size
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("syncing compacted file: %w", err)
+	}
+
+	err = os.Rename(tmpPath, s.path)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("replacing segment file: %w", err)
+	}
+
+	_ = s.file.Close()
+
+	s.file = tmp
+	s.index = index
+	s.size = offset
+	s.live = offset
+
+	return nil
+}
+
+// keys - returns keys of live not expired records.
+func (s *segment[K, V]) keys(now time.Time) []K {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys = make([]K, 0, len(s.index))
+	for key, entry := range s.index {
+		if now.Before(entry.expiredAt) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (s *segment[K, V]) len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.index)
+}
+
+func (s *segment[K, V]) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// write - appends 'rec' to the file and applies it to index. It must be called under lock.
+func (s *segment[K, V]) write(rec segmentRecord[K, V]) error {
+	var payload bytes.Buffer
+
+	err := s.codec.Encode(&payload, rec)
+	if err != nil {
+		return fmt.Errorf("encoding record: %w", err)
+	}
+
+	var data = make([]byte, segmentHeaderSize, segmentHeaderSize+payload.Len())
+	binary.LittleEndian.PutUint32(data[0:4], uint32(payload.Len()))
+	binary.LittleEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(payload.Bytes()))
+	data = append(data, payload.Bytes()...)
+
+	_, err = s.file.WriteAt(data, s.size)
+	if err != nil {
+		return fmt.Errorf("writing record: %w", err)
+	}
+
+	s.apply(rec, segmentEntry{offset: s.size, size: int64(len(data))}, s.clock.Now())
+	s.size += int64(len(data))
+
+	return nil
+}
+
+// apply - updates index with 'rec' located at 'entry'.
+func (s *segment[K, V]) apply(rec segmentRecord[K, V], entry segmentEntry, now time.Time) {
+	if prev, ok := s.index[rec.Key]; ok {
+		delete(s.index, rec.Key)
+		s.live -= prev.size
+	}
+
+	if rec.Deleted || !now.Before(rec.ExpiredAt) {
+		return
+	}
+
+	entry.expiredAt = rec.ExpiredAt
+	entry.tags = rec.Tags
+
+	s.index[rec.Key] = entry
+	s.live += entry.size
+}
+
+/*
+readRecord - reads and decodes a single record from 'r' having 'n' bytes left. Returned int64 is a record size. Record
+which length exceeds 'n' is considered to be torn, so a corrupted length is not allocated.
+*/
+func (s *segment[K, V]) readRecord(r io.Reader, n int64) (segmentRecord[K, V], int64, error) {
+	var (
+		rec    segmentRecord[K, V]
+		header [segmentHeaderSize]byte
+	)
+
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return rec, 0, err
+	}
+
+	var length = int64(binary.LittleEndian.Uint32(header[0:4]))
+	if length > n-segmentHeaderSize {
+		return rec, 0, errSegmentTorn
+	}
+
+	var payload = make([]byte, length)
+
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return rec, 0, err
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:8]) {
+		return rec, 0, errSegmentTorn
+	}
+
+	err = s.codec.Decode(bytes.NewReader(payload), &rec)
+	if err != nil {
+		return rec, 0, fmt.Errorf("decoding: %w", err)
+	}
+
+	return rec, int64(segmentHeaderSize + len(payload)), nil
+}
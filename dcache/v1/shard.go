@@ -0,0 +1,229 @@
+package dcache
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shard - is an independently locked part of the cache.
+type shard[K comparable, V any] struct {
+	mu   sync.RWMutex
+	data map[K]*item[K, V]
+	// policy - orders the same items as 'data' for eviction.
+	policy policy[K, V]
+	// expiries - orders the same items as 'data' by expiration time.
+	expiries expiries[K, V]
+	// tags - indexes keys of 'data' items by their tags. It is created once a tagged item is set.
+	tags map[string]map[K]struct{}
+	// usage - is shared among all cache shards.
+	usage *usage
+
+	listener Listener[K, V]
+	// removed - is collected under lock if listener is set and is passed to listener once lock is released.
+	removed []removal[K, V]
+}
+
+func newShard[K comparable, V any](usage *usage, policy policy[K, V], listener Listener[K, V]) *shard[K, V] {
+	return &shard[K, V]{
+		data:     make(map[K]*item[K, V]),
+		policy:   policy,
+		usage:    usage,
+		listener: listener,
+	}
+}
+
+// unlock - releases write lock and then notifies listener about items removed under this lock.
+func (s *shard[K, V]) unlock() {
+	s.notify(s.release())
+}
+
+// release - releases write lock and returns items removed under this lock. See notify().
+func (s *shard[K, V]) release() []removal[K, V] {
+	var removed = s.removed
+	s.removed = nil
+
+	s.mu.Unlock()
+
+	return removed
+}
+
+// notify - notifies listener about 'removed' items. It must be called without lock.
+func (s *shard[K, V]) notify(removed []removal[K, V]) {
+	for _, r := range removed {
+		s.listener(r.key, r.val, r.reason)
+	}
+}
+
+/*
+set - returns true if a new item was added or existing one got heavier, false if existing one was updated or a new
+one was rejected by policy at once. If 'keepTags' is true, existing item keeps its tags instead of 'tags'.
+*/
+func (s *shard[K, V]) set(
+	key K, h uint64, val V, weight int64, now, expiredAt, refreshAt time.Time, tags []string, keepTags bool,
+) bool {
+	s.mu.Lock()
+	defer s.unlock()
+
+	if it, ok := s.data[key]; ok {
+		s.notifyRemoved(it, RemovalReasonReplaced)
+
+		var heavier = weight > it.weight
+
+		s.usage.weight.Add(weight - it.weight)
+		it.val = val
+		it.weight = weight
+		it.expiredAt = expiredAt
+		it.refreshAt = refreshAt
+
+		if !keepTags {
+			s.untag(it)
+			s.tag(it, tags)
+		}
+
+		s.policy.update(it)
+		heap.Fix(&s.expiries, it.expiryI)
+
+		return heavier
+	}
+
+	it := &item[K, V]{
+		key:       key,
+		hash:      h,
+		val:       val,
+		weight:    weight,
+		expiredAt: expiredAt,
+		refreshAt: refreshAt,
+	}
+
+	s.data[key] = it
+	s.tag(it, tags)
+	heap.Push(&s.expiries, it)
+	s.usage.n.Add(1)
+	s.usage.weight.Add(weight)
+
+	if rejected := s.policy.add(it); rejected != nil {
+		s.removeItem(rejected, RemovalReasonEvicted)
+	}
+
+	s.removeSoonestIfExpired(now)
+
+	_, ok := s.data[key]
+
+	return ok
+}
+
+// get - returned 'stale' is true if the value is found, but it is to be refreshed. See WithRefresh().
+func (s *shard[K, V]) get(key K, h uint64, now time.Time) (val V, expiredAt time.Time, ok, stale bool) {
+	s.mu.RLock()
+
+	it, ok := s.data[key]
+	if !ok {
+		s.policy.miss(h)
+		s.mu.RUnlock()
+		return *new(V), time.Time{}, false, false
+	}
+
+	if now.After(it.expiredAt) {
+		s.mu.RUnlock()
+		return *new(V), time.Time{}, false, false
+	}
+
+	// Policies reorder items lazily under write lock. See policy.hit().
+	s.policy.hit(it)
+
+	val = it.val
+	expiredAt = it.expiredAt
+	stale = !it.refreshAt.IsZero() && now.After(it.refreshAt)
+	s.mu.RUnlock()
+
+	return val, expiredAt, true, stale
+}
+
+/*
+deleteExpired - deletes at most 'n' expired items in expiration order. All expired items are deleted if 'n' < 1.
+Returns number of deleted items.
+*/
+func (s *shard[K, V]) deleteExpired(now time.Time, n int) int {
+	s.mu.Lock()
+	defer s.unlock()
+
+	var deleted int
+	for ; n < 1 || deleted < n; deleted++ {
+		it, ok := s.expiries.expired(now)
+		if !ok {
+			break
+		}
+
+		s.removeItem(it, RemovalReasonExpired)
+	}
+
+	return deleted
+}
+
+func (s *shard[K, V]) len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.data)
+}
+
+func (s *shard[K, V]) invalidate(key K, reason RemovalReason) {
+	s.mu.Lock()
+	defer s.unlock()
+
+	if it, ok := s.data[key]; ok {
+		s.removeItem(it, reason)
+	}
+}
+
+// evict - removes policy victim. Returned bool is false if shard is empty.
+func (s *shard[K, V]) evict() bool {
+	s.mu.Lock()
+	defer s.unlock()
+
+	it := s.policy.victim()
+	if it != nil {
+		s.removeItem(it, RemovalReasonEvicted)
+	}
+
+	return it != nil
+}
+
+func (s *shard[K, V]) removeItem(it *item[K, V], reason RemovalReason) {
+	s.policy.remove(it)
+	heap.Remove(&s.expiries, it.expiryI)
+	s.untag(it)
+	delete(s.data, it.key)
+	s.usage.n.Add(-1)
+	s.usage.weight.Add(-it.weight)
+
+	s.notifyRemoved(it, reason)
+}
+
+// notifyRemoved - schedules listener notification about 'it' removal. See shard.unlock().
+func (s *shard[K, V]) notifyRemoved(it *item[K, V], reason RemovalReason) {
+	if s.listener == nil {
+		return
+	}
+
+	s.removed = append(s.removed, removal[K, V]{
+		key:    it.key,
+		val:    it.val,
+		reason: reason,
+	})
+}
+
+func (s *shard[K, V]) removeSoonestIfExpired(now time.Time) {
+	it, ok := s.expiries.expired(now)
+	if ok {
+		s.removeItem(it, RemovalReasonExpired)
+	}
+}
+
+// usage - is a cache usage shared among shards.
+type usage struct {
+	n      atomic.Int64
+	weight atomic.Int64
+}
@@ -0,0 +1,147 @@
+package dcache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"github.com/don-nv/go-dpkg/djson/v1"
+	"io"
+	"time"
+)
+
+// Codec - encodes Cache.Snapshot() and decodes it on Cache.Restore(). See WithCodec().
+type Codec interface {
+	Encode(w io.Writer, v any) error
+	Decode(r io.Reader, v any) error
+}
+
+var (
+	/*
+		CodecGob - is a default codec. Keys and values of interface types must have their concrete types registered
+		with gob.Register().
+	*/
+	CodecGob Codec = codecGob{}
+	// CodecJSON - encodes snapshot with djson. Keys and values must survive JSON marshalling.
+	CodecJSON Codec = codecJSON{}
+)
+
+/*
+Snapshot - writes not expired items into 'w' encoded with a codec, see WithCodec(). Each item keeps its expiration
+time, while items are written in their eviction order, so Restore() would reproduce the latter. Each shard is
+captured at once under its read lock, so a snapshot is consistent within a shard, but not across shards.
+*/
+func (c *cache[K, V]) Snapshot(w io.Writer) error {
+	var (
+		now  = c.clock.Now()
+		snap = snapshot[K, V]{
+			Items: make([]snapshotItem[K, V], 0, c.usage.n.Load()),
+		}
+	)
+
+	for _, s := range c.shards {
+		snap.Items = s.snapshot(snap.Items, now)
+	}
+
+	err := c.codec.Encode(w, snap)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Restore - reads items written by Snapshot() from 'r' and sets them keeping their expiration time. Items expired
+meanwhile are dropped. Restored items are added to cached ones, so it is intended to be called right after cache
+creation.
+*/
+func (c *cache[K, V]) Restore(r io.Reader) error {
+	return c.restoreFunc(r, c.Set)
+}
+
+// restoreFunc - is the same as Cache.Restore(), but not expired items are restored by 'set'.
+func (c *cache[K, V]) restoreFunc(r io.Reader, set func(key K, val V, ttl time.Duration, tags ...string)) error {
+	var snap snapshot[K, V]
+
+	err := c.codec.Decode(r, &snap)
+	if err != nil {
+		return fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	var now = c.clock.Now()
+
+	for _, it := range snap.Items {
+		ttl := it.ExpiredAt.Sub(now)
+		if ttl <= 0 {
+			continue
+		}
+
+		set(it.Key, it.Val, ttl, it.Tags...)
+	}
+
+	return nil
+}
+
+// snapshot - appends not expired shard items to 'items' in eviction order. See policy.walk().
+func (s *shard[K, V]) snapshot(items []snapshotItem[K, V], now time.Time) []snapshotItem[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.policy.walk(func(it *item[K, V]) bool {
+		if now.Before(it.expiredAt) {
+			items = append(items, snapshotItem[K, V]{
+				Key:       it.key,
+				Val:       it.val,
+				ExpiredAt: it.expiredAt,
+				Tags:      it.tags,
+			})
+		}
+
+		return true
+	})
+
+	return items
+}
+
+// snapshot - is encoded as a single value. Fields are exported to be encoded.
+type snapshot[K comparable, V any] struct {
+	Items []snapshotItem[K, V]
+}
+
+type snapshotItem[K comparable, V any] struct {
+	Key       K
+	Val       V
+	ExpiredAt time.Time
+	Tags      []string
+}
+
+type codecGob struct{}
+
+func (codecGob) Encode(w io.Writer, v any) error {
+	return gob.NewEncoder(w).Encode(v)
+}
+
+func (codecGob) Decode(r io.Reader, v any) error {
+	return gob.NewDecoder(r).Decode(v)
+}
+
+type codecJSON struct{}
+
+func (codecJSON) Encode(w io.Writer, v any) error {
+	data, err := djson.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+func (codecJSON) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return djson.Unmarshal(data, v)
+}
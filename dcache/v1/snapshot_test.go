@@ -0,0 +1,45 @@
+package dcache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SnapshotRestore(t *testing.T) {
+	for _, codec := range []Codec{CodecGob, CodecJSON} {
+		var (
+			cache = NewCache[string, int]().WithCapacity(3).WithCodec(codec)
+			buf   bytes.Buffer
+		)
+
+		cache.Set("key1", 1, 0)
+		cache.Set("key2", 2, 0)
+		cache.Set("key3", 3, 0)
+		cache.Get("key1")
+		cache.Set("expiring", 4, 50*time.Millisecond)
+
+		require.NoError(t, cache.Snapshot(&buf))
+
+		time.Sleep(100 * time.Millisecond)
+
+		restored := NewCache[string, int]().WithCapacity(3).WithCodec(codec)
+		require.NoError(t, restored.Restore(&buf))
+		require.EqualValues(t, 2, restored.Len())
+
+		_, ok := restored.Get("expiring")
+		require.False(t, ok)
+
+		// "key1" is used more recently than "key3", so the latter is evicted.
+		restored.Set("key4", 4, 0)
+		restored.Set("key5", 5, 0)
+
+		for key, expected := range map[string]bool{"key1": true, "key3": false, "key4": true, "key5": true} {
+			_, ok = restored.Get(key)
+			require.EqualValues(t, expected, ok, fmt.Sprintf("%T: %s", codec, key))
+		}
+	}
+}
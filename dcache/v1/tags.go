@@ -0,0 +1,97 @@
+package dcache
+
+import (
+	"slices"
+	"strings"
+)
+
+// InvalidateTag - removes all items set with 'tag' at once. See Cache.Set().
+func (c *cache[K, V]) InvalidateTag(tag string) {
+	c.lockedAll(func(s *shard[K, V]) {
+		for key := range s.tags[tag] {
+			s.removeItem(s.data[key], RemovalReasonInvalidated)
+		}
+	})
+}
+
+/*
+InvalidatePrefix - removes all items which keys start with 'prefix' at once. All items are looked up, so it takes a
+time proportional to cache length, while all shards are locked.
+*/
+func InvalidatePrefix[K ~string, V any](c Cache[K, V], prefix string) {
+	c.invalidateFunc(func(key K) bool {
+		return strings.HasPrefix(string(key), prefix)
+	})
+}
+
+// invalidateFunc - removes all items which keys 'match' at once.
+func (c *cache[K, V]) invalidateFunc(match func(key K) bool) {
+	c.lockedAll(func(s *shard[K, V]) {
+		for key, it := range s.data {
+			if match(key) {
+				s.removeItem(it, RemovalReasonInvalidated)
+			}
+		}
+	})
+}
+
+/*
+lockedAll - calls 'f' for each shard while all of them are locked, so 'f' changes are observed at once. Listener is
+notified once all shards are unlocked.
+*/
+func (c *cache[K, V]) lockedAll(f func(s *shard[K, V])) {
+	for _, s := range c.shards {
+		s.mu.Lock()
+	}
+
+	for _, s := range c.shards {
+		f(s)
+	}
+
+	var removed = make([][]removal[K, V], len(c.shards))
+	for i, s := range c.shards {
+		removed[i] = s.release()
+	}
+
+	for i, s := range c.shards {
+		s.notify(removed[i])
+	}
+}
+
+// tag - indexes 'it' by 'tags'.
+func (s *shard[K, V]) tag(it *item[K, V], tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	// Caller may reuse 'tags'.
+	it.tags = slices.Clone(tags)
+
+	if s.tags == nil {
+		s.tags = make(map[string]map[K]struct{})
+	}
+
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[K]struct{})
+			s.tags[tag] = keys
+		}
+
+		keys[it.key] = struct{}{}
+	}
+}
+
+// untag - removes 'it' from tags index.
+func (s *shard[K, V]) untag(it *item[K, V]) {
+	for _, tag := range it.tags {
+		keys := s.tags[tag]
+		delete(keys, it.key)
+
+		if len(keys) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+
+	it.tags = nil
+}
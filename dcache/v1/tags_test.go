@@ -0,0 +1,60 @@
+package dcache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_InvalidateTag(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		invalidated []string
+	)
+
+	cache := NewCache[string, int]().
+		WithEvictionListener(func(key string, val int, reason RemovalReason) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if reason == RemovalReasonInvalidated {
+				invalidated = append(invalidated, key)
+			}
+		})
+
+	cache.Set("user:1", 1, 0, "user:1")
+	cache.Set("user:1:orders", 2, 0, "user:1", "orders")
+	cache.Set("user:2:orders", 3, 0, "user:2", "orders")
+	cache.Set("user:2", 4, 0, "user:2")
+	// Tags are replaced.
+	cache.Set("user:2", 4, 0)
+
+	cache.InvalidateTag("user:1")
+	require.ElementsMatch(t, []string{"user:1", "user:1:orders"}, invalidated)
+	require.EqualValues(t, 2, cache.Len())
+
+	cache.InvalidateTag("user:2")
+	require.EqualValues(t, 1, cache.Len())
+
+	_, ok := cache.Get("user:2")
+	require.True(t, ok)
+
+	cache.InvalidateTag("orders")
+	require.EqualValues(t, 1, cache.Len())
+	require.ElementsMatch(t, []string{"user:1", "user:1:orders", "user:2:orders"}, invalidated)
+}
+
+func TestInvalidatePrefix(t *testing.T) {
+	cache := NewCache[string, int]()
+
+	cache.Set("user:1", 1, 0)
+	cache.Set("user:1:orders", 2, 0)
+	cache.Set("user:2", 3, 0)
+
+	InvalidatePrefix(cache, "user:1")
+	require.EqualValues(t, 1, cache.Len())
+
+	_, ok := cache.Get("user:2")
+	require.True(t, ok)
+}
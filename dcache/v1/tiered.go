@@ -0,0 +1,334 @@
+package dcache
+
+import (
+	"context"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/don-nv/go-dpkg/dmetrics/dprom/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"io"
+	"slices"
+	"time"
+)
+
+// TieredCache - is a Cache having L2, which must be closed once cache is not used anymore. See NewTieredCache().
+type TieredCache[K comparable, V any] interface {
+	Cache[K, V]
+	Close() error
+}
+
+/*
+NewTieredCache - returns a two-tier cache: in-memory Cache is L1, while L2 is an append-only segment file at 'path'
+holding items encoded with 'codec', e.g. CodecGob. Items are written through both tiers. L1 misses fall through to L2
+and L2 hits are promoted to L1, so L1 options, e.g. WithCapacity(), limit hot items only. Items kept in an existing
+'path' file are available at once. Meanwhile:
+  - L2 failures are logged and are considered to be misses, see WithLogger();
+  - Cache.Len() counts L2 items, which include L1 ones, while Cache.Weight() is related to L1 only;
+  - Eviction listener and metrics are related to L1 only;
+  - Cache.Snapshot() covers L1 only, since L2 is persistent itself, while Cache.Restore() writes through both tiers;
+  - L2 expired items are reclaimed and its file is compacted by Cache.DeleteExpiredCache() and Cache.Running().
+*/
+func NewTieredCache[K comparable, V any](path string, codec Codec) (TieredCache[K, V], error) {
+	l2, err := openSegment[K, V](path, codec)
+	if err != nil {
+		return nil, fmt.Errorf("opening segment: %w", err)
+	}
+
+	return &tiered[K, V]{
+		l1: NewCache[K, V]().(*cache[K, V]), //nolint:errcheck
+		l2: l2,
+	}, nil
+}
+
+func (t *tiered[K, V]) Set(key K, val V, ttl time.Duration, tags ...string) {
+	if ttl <= 0 {
+		ttl = t.l1.ttl
+	}
+
+	t.setL2(key, val, ttl, tags)
+	t.l1.Set(key, val, ttl, tags...)
+}
+
+func (t *tiered[K, V]) Get(key K) (V, bool) {
+	val, _, ok := t.GetWithExpiry(key)
+	return val, ok
+}
+
+func (t *tiered[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	if val, expiredAt, ok := t.l1.GetWithExpiry(key); ok {
+		return val, expiredAt, true
+	}
+
+	var now = t.l1.clock.Now()
+
+	rec, ok := t.getL2(key, now)
+	if !ok {
+		return *new(V), time.Time{}, false
+	}
+
+	t.l1.Set(key, rec.Val, rec.ExpiredAt.Sub(now), rec.Tags...)
+
+	return rec.Val, rec.ExpiredAt, true
+}
+
+// Peek - see Cache.Peek(). L2 value is not promoted.
+func (t *tiered[K, V]) Peek(key K) (V, time.Time, bool) {
+	if val, expiredAt, ok := t.l1.Peek(key); ok {
+		return val, expiredAt, true
+	}
+
+	rec, ok := t.getL2(key, t.l1.clock.Now())
+	if !ok {
+		return *new(V), time.Time{}, false
+	}
+
+	return rec.Val, rec.ExpiredAt, true
+}
+
+func (t *tiered[K, V]) Touch(key K, ttl time.Duration) bool {
+	if ttl <= 0 {
+		ttl = t.l1.ttl
+	}
+
+	var now = t.l1.clock.Now()
+
+	rec, ok := t.getL2(key, now)
+	if !ok {
+		return t.l1.Touch(key, ttl)
+	}
+
+	t.logL2E(t.l2.set(key, rec.Val, now.Add(ttl), rec.Tags), "touching l2 item")
+	t.l1.Touch(key, ttl)
+
+	return true
+}
+
+// Keys - returns L2 keys, which include L1 ones. See Cache.Keys().
+func (t *tiered[K, V]) Keys() []K {
+	return t.l2.keys(t.l1.clock.Now())
+}
+
+/*
+Range - ranges over L2 items, which include L1 ones. Items are read one by one, so an item changed concurrently may or
+may not be observed.
+*/
+func (t *tiered[K, V]) Range(f func(key K, val V, expiredAt time.Time) bool) {
+	var now = t.l1.clock.Now()
+
+	for _, key := range t.l2.keys(now) {
+		rec, ok := t.getL2(key, now)
+		if !ok {
+			continue
+		}
+
+		if !f(key, rec.Val, rec.ExpiredAt) {
+			return
+		}
+	}
+}
+
+// GetOrLoad - see Cache.GetOrLoad(). Loaded value is written through both tiers.
+func (t *tiered[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V]) (V, error) {
+	if val, ok := t.Get(key); ok {
+		return val, nil
+	}
+
+	return t.l1.GetOrLoad(ctx, key, func(ctx context.Context) (V, time.Duration, error) {
+		val, ttl, err := loader(ctx)
+		if err == nil {
+			t.setL2(key, val, ttl, nil)
+		}
+
+		return val, ttl, err
+	})
+}
+
+func (t *tiered[K, V]) DeleteExpiredCache() {
+	t.l1.DeleteExpiredCache()
+	t.deleteExpiredL2()
+}
+
+// Running - runs L1 janitor (see Cache.Running()) and reclaims L2 expired items once in a janitor interval.
+func (t *tiered[K, V]) Running(ctx context.Context) error {
+	var errC = make(chan error, 1)
+	go func() {
+		errC <- t.l1.Running(ctx)
+	}()
+
+	var ticker = t.l1.clock.NewTicker(t.l1.janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return <-errC
+
+		case <-ticker.C():
+			t.deleteExpiredL2()
+		}
+	}
+}
+
+func (t *tiered[K, V]) Len() int {
+	return t.l2.len()
+}
+
+func (t *tiered[K, V]) Weight() int64 {
+	return t.l1.Weight()
+}
+
+func (t *tiered[K, V]) InvalidateKey(key K) {
+	t.logL2E(t.l2.delete(key), "deleting l2 item")
+	t.l1.InvalidateKey(key)
+}
+
+func (t *tiered[K, V]) InvalidateTag(tag string) {
+	t.logL2E(t.l2.deleteTag(tag), "deleting l2 tag")
+	t.l1.InvalidateTag(tag)
+}
+
+func (t *tiered[K, V]) Snapshot(w io.Writer) error {
+	return t.l1.Snapshot(w)
+}
+
+// Restore - see Cache.Restore(). Items are written through both tiers.
+func (t *tiered[K, V]) Restore(r io.Reader) error {
+	return t.l1.restoreFunc(r, t.Set)
+}
+
+// Close - closes L2 file. Cache must not be used afterwards.
+func (t *tiered[K, V]) Close() error {
+	return t.l2.close()
+}
+
+func (t *tiered[K, V]) shardOf(key K) *shard[K, V] {
+	return t.l1.shardOf(key)
+}
+
+func (t *tiered[K, V]) invalidateFunc(match func(key K) bool) {
+	err := t.l2.deleteFunc(func(key K, _ segmentEntry) bool {
+		return match(key)
+	})
+	t.logL2E(err, "deleting l2 items")
+
+	t.l1.invalidateFunc(match)
+}
+
+func (t *tiered[K, V]) WithCapacity(capacity int) Cache[K, V] {
+	t.l1.WithCapacity(capacity)
+	return t
+}
+
+func (t *tiered[K, V]) WithDuration(ttl time.Duration) Cache[K, V] {
+	t.l1.WithDuration(ttl)
+	return t
+}
+
+func (t *tiered[K, V]) WithShards(n int) Cache[K, V] {
+	t.l1.WithShards(n)
+	return t
+}
+
+func (t *tiered[K, V]) WithHasher(f func(key K) uint64) Cache[K, V] {
+	t.l1.WithHasher(f)
+	return t
+}
+
+func (t *tiered[K, V]) WithErrorTTL(ttl time.Duration) Cache[K, V] {
+	t.l1.WithErrorTTL(ttl)
+	return t
+}
+
+func (t *tiered[K, V]) WithJanitorInterval(d time.Duration) Cache[K, V] {
+	t.l1.WithJanitorInterval(d)
+	return t
+}
+
+func (t *tiered[K, V]) WithEvictionListener(f Listener[K, V]) Cache[K, V] {
+	t.l1.WithEvictionListener(f)
+	return t
+}
+
+func (t *tiered[K, V]) WithPolicy(p Policy) Cache[K, V] {
+	t.l1.WithPolicy(p)
+	return t
+}
+
+func (t *tiered[K, V]) WithMaxWeight(weight int64, weigher func(key K, val V) int64) Cache[K, V] {
+	t.l1.WithMaxWeight(weight, weigher)
+	return t
+}
+
+func (t *tiered[K, V]) WithMetrics(entry dprom.Entry) Cache[K, V] {
+	t.l1.WithMetrics(entry)
+	return t
+}
+
+// WithCodec - sets L1 Cache.Snapshot() codec. L2 codec is set on NewTieredCache().
+func (t *tiered[K, V]) WithCodec(codec Codec) Cache[K, V] {
+	t.l1.WithCodec(codec)
+	return t
+}
+
+// WithRefresh - see Cache.WithRefresh(). Refreshed values are written through both tiers.
+func (t *tiered[K, V]) WithRefresh(softTTL time.Duration, loader KeyLoader[K, V]) Cache[K, V] {
+	t.l1.WithRefresh(softTTL, func(ctx context.Context, key K) (V, time.Duration, error) {
+		val, ttl, err := loader(ctx, key)
+		if err == nil {
+			t.setL2(key, val, ttl, nil)
+		}
+
+		return val, ttl, err
+	})
+
+	return t
+}
+
+func (t *tiered[K, V]) WithLogger(log dlog.Logger) Cache[K, V] {
+	t.l1.WithLogger(log)
+	return t
+}
+
+// WithClock - sets both L1 and L2 clock. L2 items loaded on NewTieredCache() are checked for expiration by system time.
+func (t *tiered[K, V]) WithClock(clock dtime.Clock) Cache[K, V] {
+	t.l1.WithClock(clock)
+	t.l2.clock = clock
+
+	return t
+}
+
+// setL2 - is the same as Cache.Set(), but for L2 only.
+func (t *tiered[K, V]) setL2(key K, val V, ttl time.Duration, tags []string) {
+	if ttl <= 0 {
+		ttl = t.l1.ttl
+	}
+
+	t.logL2E(t.l2.set(key, val, t.l1.clock.Now().Add(ttl), slices.Clone(tags)), "setting l2 item")
+}
+
+// getL2 - returns L2 record. Failure is logged and is considered to be a miss.
+func (t *tiered[K, V]) getL2(key K, now time.Time) (segmentRecord[K, V], bool) {
+	rec, ok, err := t.l2.get(key, now)
+	if err != nil {
+		t.l1.log.E().Any("key", key).Writef("getting l2 item: %s", err)
+		return segmentRecord[K, V]{}, false
+	}
+
+	return rec, ok
+}
+
+func (t *tiered[K, V]) deleteExpiredL2() {
+	t.l2.deleteExpired(t.l1.clock.Now())
+	t.logL2E(t.l2.compactIfNeeded(), "compacting l2")
+}
+
+func (t *tiered[K, V]) logL2E(err error, msg string) {
+	if err != nil {
+		t.l1.log.E().Writef("%s: %s", msg, err)
+	}
+}
+
+type tiered[K comparable, V any] struct {
+	l1 *cache[K, V]
+	l2 *segment[K, V]
+}
@@ -0,0 +1,183 @@
+package dcache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieredCache(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "cache.seg")
+
+	cache, err := NewTieredCache[string, int](path, CodecGob)
+	require.NoError(t, err)
+
+	cache.WithCapacity(1).WithShards(1)
+
+	cache.Set("key1", 1, 0, "tag")
+	cache.Set("key2", 2, 0)
+	cache.Set("expiring", 3, 50*time.Millisecond)
+	cache.Set("invalidated", 4, 0)
+	cache.InvalidateKey("invalidated")
+
+	// "key1" is evicted from L1, but is promoted from L2.
+	v, ok := cache.Get("key1")
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+	require.EqualValues(t, 3, cache.Len())
+
+	require.NoError(t, cache.Close())
+
+	time.Sleep(100 * time.Millisecond)
+
+	cache, err = NewTieredCache[string, int](path, CodecGob)
+	require.NoError(t, err)
+
+	require.EqualValues(t, 2, cache.Len())
+
+	for key, expected := range map[string]bool{"key1": true, "key2": true, "expiring": false, "invalidated": false} {
+		_, ok = cache.Get(key)
+		require.EqualValues(t, expected, ok, key)
+	}
+
+	cache.InvalidateTag("tag")
+	_, ok = cache.Get("key1")
+	require.False(t, ok)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestSegment_Compact(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "cache.seg")
+
+	s, err := openSegment[string, int](path, CodecJSON)
+	require.NoError(t, err)
+
+	var expiredAt = time.Now().Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.set("key", i, expiredAt, nil))
+	}
+	require.NoError(t, s.set("expiring", 0, time.Now().Add(10*time.Millisecond), nil))
+
+	time.Sleep(20 * time.Millisecond)
+
+	sizeBefore := s.size
+	require.NoError(t, s.compact(time.Now()))
+	require.Less(t, s.size, sizeBefore/5)
+	require.EqualValues(t, s.size, s.live)
+
+	rec, ok, err := s.get("key", time.Now())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 9, rec.Val)
+
+	sizeCompacted := s.size
+	require.NoError(t, s.close())
+
+	// Torn tail is truncated on open.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{42, 0, 0, 0, 1})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	s, err = openSegment[string, int](path, CodecJSON)
+	require.NoError(t, err)
+	require.EqualValues(t, sizeCompacted, s.size)
+	require.EqualValues(t, 1, s.len())
+	require.NoError(t, s.close())
+}
+
+func TestTieredCache_Inspection(t *testing.T) {
+	cache, err := NewTieredCache[string, int](filepath.Join(t.TempDir(), "cache.seg"), CodecGob)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, cache.Close())
+	}()
+
+	cache.WithCapacity(1).WithShards(1)
+
+	cache.Set("key1", 1, 50*time.Millisecond)
+	cache.Set("key2", 2, 0)
+
+	v, _, ok := cache.Peek("key1")
+	require.True(t, ok)
+	require.EqualValues(t, 1, v)
+	require.ElementsMatch(t, []string{"key2"}, cache.(*tiered[string, int]).l1.Keys())
+
+	require.True(t, cache.Touch("key1", time.Hour))
+	time.Sleep(100 * time.Millisecond)
+
+	require.ElementsMatch(t, []string{"key1", "key2"}, cache.Keys())
+
+	var sum int
+	cache.Range(func(_ string, val int, _ time.Time) bool {
+		sum += val
+		return true
+	})
+	require.EqualValues(t, 3, sum)
+}
+
+func TestTieredCache_Restore(t *testing.T) {
+	var (
+		source = NewCache[string, int]()
+		snap   bytes.Buffer
+	)
+
+	source.Set("key1", 1, time.Hour, "tag")
+	source.Set("key2", 2, time.Hour)
+	require.NoError(t, source.Snapshot(&snap))
+
+	cache, err := NewTieredCache[string, int](filepath.Join(t.TempDir(), "cache.seg"), CodecGob)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	require.NoError(t, cache.Restore(&snap))
+	require.EqualValues(t, 2, cache.Len())
+
+	var l2 = cache.(*tiered[string, int]).l2
+	for _, key := range []string{"key1", "key2"} {
+		_, ok, err := l2.get(key, time.Now())
+		require.NoError(t, err)
+		require.True(t, ok, key)
+	}
+
+	cache.InvalidateKey("key2")
+	require.EqualValues(t, 1, cache.Len())
+}
+
+func TestSegment_CorruptedLength(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "cache.seg")
+
+	s, err := openSegment[string, int](path, CodecJSON)
+	require.NoError(t, err)
+	require.NoError(t, s.set("key", 1, time.Now().Add(time.Hour), nil))
+
+	var size = s.size
+	require.NoError(t, s.close())
+
+	// Header declares a 4 GiB payload, which is not allocated.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	s, err = openSegment[string, int](path, CodecJSON)
+	require.NoError(t, err)
+
+	runtime.ReadMemStats(&after)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+	require.EqualValues(t, size, s.size)
+	require.EqualValues(t, 1, s.len())
+	require.NoError(t, s.close())
+}
@@ -0,0 +1,243 @@
+package dcache
+
+import (
+	"container/list"
+	"sync/atomic"
+)
+
+const (
+	segmentNone uint8 = iota
+	segmentWindow
+	segmentProbation
+	segmentProtected
+)
+
+const (
+	// tinyLFUWindowPercent - is a share of shard capacity taken by window LRU.
+	tinyLFUWindowPercent = 1
+	// tinyLFUProtectedPercent - is a share of main segmented LRU taken by protected segment.
+	tinyLFUProtectedPercent = 80
+)
+
+/*
+tinyLFU - see PolicyTinyLFU. Window and both main segments are lazily ordered LRUs like lru. Item accessed in probation
+segment gets promoted to protected one once it is considered for eviction. Items overflowing protected segment are
+demoted back to probation one.
+*/
+type tinyLFU[K comparable, V any] struct {
+	sketch    *sketch
+	window    *list.List
+	probation *list.List
+	protected *list.List
+
+	windowCap    int
+	mainCap      int
+	protectedCap int
+}
+
+// newTinyLFU - negative 'capacity' means main segment is not limited, so admission never happens.
+func newTinyLFU[K comparable, V any](capacity int) *tinyLFU[K, V] {
+	var windowCap = max(1, capacity*tinyLFUWindowPercent/100)
+
+	t := tinyLFU[K, V]{
+		sketch:    newSketch(capacity),
+		window:    list.New(),
+		probation: list.New(),
+		protected: list.New(),
+		windowCap: windowCap,
+		mainCap:   -1,
+	}
+
+	if capacity >= 0 {
+		t.mainCap = max(0, capacity-windowCap)
+	}
+	t.protectedCap = t.mainCap * tinyLFUProtectedPercent / 100
+
+	return &t
+}
+
+func (t *tinyLFU[K, V]) add(it *item[K, V]) *item[K, V] {
+	t.sketch.increment(it.hash)
+	t.push(it, segmentWindow)
+
+	if t.window.Len() <= t.windowCap {
+		return nil
+	}
+
+	candidate := listVictim[K, V](t.window)
+	t.detach(candidate)
+
+	if t.mainCap < 0 || t.probation.Len()+t.protected.Len() < t.mainCap {
+		t.push(candidate, segmentProbation)
+
+		return nil
+	}
+
+	victim := t.mainVictim()
+	if victim == nil || t.sketch.estimate(candidate.hash) <= t.sketch.estimate(victim.hash) {
+		return candidate
+	}
+
+	t.push(candidate, segmentProbation)
+
+	return victim
+}
+
+func (t *tinyLFU[K, V]) update(it *item[K, V]) {
+	t.sketch.increment(it.hash)
+	it.accessed.Store(true)
+}
+
+func (t *tinyLFU[K, V]) hit(it *item[K, V]) {
+	t.sketch.increment(it.hash)
+
+	if !it.accessed.Load() {
+		it.accessed.Store(true)
+	}
+}
+
+func (t *tinyLFU[K, V]) miss(h uint64) {
+	t.sketch.increment(h)
+}
+
+func (t *tinyLFU[K, V]) remove(it *item[K, V]) {
+	t.detach(it)
+}
+
+func (t *tinyLFU[K, V]) victim() *item[K, V] {
+	if it := t.mainVictim(); it != nil {
+		return it
+	}
+
+	return listVictim[K, V](t.window)
+}
+
+// walk - walks probation segment first, then window and protected segments.
+func (t *tinyLFU[K, V]) walk(f func(it *item[K, V]) bool) {
+	_ = listWalk[K, V](t.probation, f) &&
+		listWalk[K, V](t.window, f) &&
+		listWalk[K, V](t.protected, f)
+}
+
+// mainVictim - returns the least recently used item of probation segment promoting accessed ones to protected one.
+func (t *tinyLFU[K, V]) mainVictim() *item[K, V] {
+	for el := t.probation.Back(); el != nil; el = t.probation.Back() {
+		it := el.Value.(*item[K, V]) //nolint:errcheck
+		if !it.accessed.Load() {
+			return it
+		}
+
+		it.accessed.Store(false)
+		t.detach(it)
+		t.push(it, segmentProtected)
+
+		for t.protected.Len() > t.protectedCap {
+			demoted := t.protected.Back().Value.(*item[K, V]) //nolint:errcheck
+			t.detach(demoted)
+			t.push(demoted, segmentProbation)
+		}
+	}
+
+	return listVictim[K, V](t.protected)
+}
+
+func (t *tinyLFU[K, V]) push(it *item[K, V], segment uint8) {
+	it.segment = segment
+
+	switch segment {
+	case segmentWindow:
+		it.el = t.window.PushFront(it)
+
+	case segmentProbation:
+		it.el = t.probation.PushFront(it)
+
+	case segmentProtected:
+		it.el = t.protected.PushFront(it)
+	}
+}
+
+func (t *tinyLFU[K, V]) detach(it *item[K, V]) {
+	switch it.segment {
+	case segmentWindow:
+		t.window.Remove(it.el)
+
+	case segmentProbation:
+		t.probation.Remove(it.el)
+
+	case segmentProtected:
+		t.protected.Remove(it.el)
+	}
+
+	it.segment = segmentNone
+}
+
+const (
+	sketchRowsN = 4
+	// sketchCounterMax - counters saturate at this value.
+	sketchCounterMax = 15
+	// sketchWidthMin - is used for small and not limited shards.
+	sketchWidthMin = 64
+	// sketchResetFactor - counters are halved once increments number reaches sketch width * factor.
+	sketchResetFactor = 10
+)
+
+/*
+sketch - is a count-min sketch estimating items use frequency. Counters get halved periodically, so estimations
+reflect recent history. It is safe to be used concurrently. Concurrent increments may get lost, which is acceptable for
+estimations.
+*/
+type sketch struct {
+	counters   []atomic.Uint32
+	mask       uint64
+	increments atomic.Int64
+	resetN     int64
+}
+
+func newSketch(capacity int) *sketch {
+	var width = sketchWidthMin
+	for width < capacity {
+		width <<= 1
+	}
+
+	return &sketch{
+		counters: make([]atomic.Uint32, sketchRowsN*width),
+		mask:     uint64(width - 1),
+		resetN:   int64(width * sketchResetFactor),
+	}
+}
+
+func (s *sketch) increment(h uint64) {
+	for i := 0; i < sketchRowsN; i++ {
+		c := &s.counters[s.index(h, i)]
+		if c.Load() < sketchCounterMax {
+			c.Add(1)
+		}
+	}
+
+	if s.increments.Add(1) >= s.resetN {
+		s.increments.Store(0)
+		s.reset()
+	}
+}
+
+func (s *sketch) estimate(h uint64) uint32 {
+	var n uint32 = sketchCounterMax
+	for i := 0; i < sketchRowsN; i++ {
+		n = min(n, s.counters[s.index(h, i)].Load())
+	}
+
+	return n
+}
+
+func (s *sketch) reset() {
+	for i := range s.counters {
+		s.counters[i].Store(s.counters[i].Load() / 2)
+	}
+}
+
+// index - returns counter index in 'row' for hash 'h'.
+func (s *sketch) index(h uint64, row int) uint64 {
+	var width = s.mask + 1
+
+	return uint64(row)*width + hashUint64(h+uint64(row)*0x9e3779b97f4a7c15)&s.mask
+}
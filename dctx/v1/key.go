@@ -0,0 +1,206 @@
+package dctx
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"sync"
+)
+
+/*
+Key - is a typed context key. Keys are registered once created by NewKey(), so packages propagating context values,
+e.g. dlog and dhttp, pick up each registered key having a log field or a header respectively. Values are formatted and
+parsed by the key codec, see Key.WithCodec().
+*/
+type Key[T any] struct {
+	name     string
+	header   string
+	logField string
+	format   func(v T) string
+	parse    func(s string) (T, error)
+}
+
+// AnyKey - is a type erased Key. See RangeKeys().
+type AnyKey interface {
+	Name() string
+	// Header - returns HTTP header name values are propagated by or "" if there is none.
+	Header() string
+	// LogField - returns log field name values are logged by or "" if there is none.
+	LogField() string
+	// Format - returns 'ctx' value formatted by the key codec. Returned bool is false if 'ctx' has no value.
+	Format(ctx context.Context) (string, bool)
+	// WithParsed - is the same as Key.With(), but parses 's' by the key codec.
+	WithParsed(ctx context.Context, s string) (context.Context, error)
+}
+
+type KeyOption func(options *keyOptions)
+
+type keyOptions struct {
+	header   string
+	logField string
+}
+
+// KeyOptionWithHeader - maps Key to 'header', e.g. "X-Tenant-Id".
+func KeyOptionWithHeader(header string) KeyOption {
+	return func(options *keyOptions) {
+		options.header = header
+	}
+}
+
+// KeyOptionWithLogField - maps Key to 'field', e.g. "tenant_id".
+func KeyOptionWithLogField(field string) KeyOption {
+	return func(options *keyOptions) {
+		options.logField = field
+	}
+}
+
+/*
+NewKey - creates and registers a Key. It is intended to be called on package initialization, e.g. to declare a
+package variable. Panics if a key 'name' is already registered.
+
+By default, values are formatted by fmt.Stringer, encoding.TextMarshaler or fmt.Sprint(), while parsed values are
+accepted as is if T is string or by encoding.TextUnmarshaler implemented by *T. See Key.WithCodec().
+*/
+func NewKey[T any](name string, options ...KeyOption) *Key[T] {
+	var o keyOptions
+	for _, option := range options {
+		option(&o)
+	}
+
+	var k = &Key[T]{
+		name:     name,
+		header:   o.header,
+		logField: o.logField,
+		format:   keyFormatDefault[T],
+		parse:    keyParseDefault[T],
+	}
+
+	keysRegister(k)
+
+	return k
+}
+
+// WithCodec - replaces default codec and returns 'k'. See NewKey().
+func (k *Key[T]) WithCodec(format func(v T) string, parse func(s string) (T, error)) *Key[T] {
+	k.format = format
+	k.parse = parse
+
+	return k
+}
+
+// With - creates 'ctx' child, adds 'v' and returns it.
+func (k *Key[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Get - returns 'ctx' value. Returned bool is false if there is none.
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	var v, ok = ctx.Value(k).(T)
+
+	return v, ok
+}
+
+// Option - is the same as Key.With(), but an Option.
+func (k *Key[T]) Option(v T) Option {
+	return func(ctx context.Context) context.Context {
+		return k.With(ctx, v)
+	}
+}
+
+func (k *Key[T]) Name() string {
+	return k.name
+}
+
+func (k *Key[T]) Header() string {
+	return k.header
+}
+
+func (k *Key[T]) LogField() string {
+	return k.logField
+}
+
+func (k *Key[T]) Format(ctx context.Context) (string, bool) {
+	v, ok := k.Get(ctx)
+	if !ok {
+		return "", false
+	}
+
+	return k.format(v), true
+}
+
+func (k *Key[T]) WithParsed(ctx context.Context, s string) (context.Context, error) {
+	v, err := k.parse(s)
+	if err != nil {
+		return ctx, fmt.Errorf("parsing %q key value: %w", k.name, err)
+	}
+
+	return k.With(ctx, v), nil
+}
+
+func keyFormatDefault[T any](v T) string {
+	switch v := any(v).(type) {
+	case string:
+		return v
+
+	case fmt.Stringer:
+		return v.String()
+
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
+
+func keyParseDefault[T any](s string) (T, error) {
+	var v T
+
+	switch p := any(&v).(type) {
+	case *string:
+		*p = s
+
+	case encoding.TextUnmarshaler:
+		err := p.UnmarshalText([]byte(s))
+		if err != nil {
+			return v, err
+		}
+
+	default:
+		return v, fmt.Errorf("no parser for %T", v)
+	}
+
+	return v, nil
+}
+
+var keys struct {
+	mu   sync.RWMutex
+	list []AnyKey
+}
+
+func keysRegister(k AnyKey) {
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+
+	for _, registered := range keys.list {
+		if registered.Name() == k.Name() {
+			panic(fmt.Sprintf("dctx key %q is already registered", k.Name()))
+		}
+	}
+
+	keys.list = append(keys.list, k)
+}
+
+// RangeKeys - calls 'f' for each registered key in registration order until 'f' returns false. See NewKey().
+func RangeKeys(f func(k AnyKey) bool) {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+
+	for _, k := range keys.list {
+		if !f(k) {
+			return
+		}
+	}
+}
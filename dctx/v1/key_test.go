@@ -0,0 +1,51 @@
+package dctx_test
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+var keyTestVersion = dctx.NewKey[int]("test_version", dctx.KeyOptionWithHeader("X-Test-Version")).
+	WithCodec(strconv.Itoa, strconv.Atoi)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	var ctx = dctx.New(keyTestVersion.Option(2))
+
+	v, ok := keyTestVersion.Get(ctx)
+	require.True(t, ok)
+	require.Equal(t, 2, v)
+
+	_, ok = keyTestVersion.Get(context.Background())
+	require.False(t, ok)
+
+	s, ok := keyTestVersion.Format(ctx)
+	require.True(t, ok)
+	require.Equal(t, "2", s)
+
+	ctx, err := keyTestVersion.WithParsed(ctx, "3")
+	require.NoError(t, err)
+	require.Equal(t, 3, func() int { v, _ := keyTestVersion.Get(ctx); return v }())
+
+	_, err = keyTestVersion.WithParsed(ctx, "three")
+	require.Error(t, err)
+
+	require.Equal(t, "id", dctx.GoID(dctx.WithGoID(ctx, "id")))
+}
+
+func TestRangeKeys(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+	dctx.RangeKeys(func(k dctx.AnyKey) bool {
+		names = append(names, k.Name())
+		return true
+	})
+
+	require.Subset(t, names, []string{"go_id", "x_req_id", "test_version"})
+	require.Panics(t, func() { dctx.NewKey[string]("go_id") })
+}
@@ -9,26 +9,79 @@ func newID() string {
 	return uuid.NewString()
 }
 
-type keyGoID struct{}
+var (
+	// KeyGoID - is a goroutine id key logged as "go_id". See WithGoID().
+	KeyGoID = NewKey[string]("go_id", KeyOptionWithLogField("go_id"))
+	// KeyParentGoID - is a parent goroutine id key logged as "parent_go_id". See WithNewGoID().
+	KeyParentGoID = NewKey[string]("parent_go_id", KeyOptionWithLogField("parent_go_id"))
+	/*
+		KeyXRequestID - is a request id key logged as "x_req_id". It has no header mapping, since its header is
+		propagated by dedicated dhttp options generating missing ids. See WithXRequestID().
+	*/
+	KeyXRequestID = NewKey[string]("x_req_id", KeyOptionWithLogField("x_req_id"))
+)
+
+type (
+	keyGoDepth      struct{}
+	keyGoDepthLimit struct{}
+)
 
-// WithNewGoID - creates 'ctx' child, adds respective value and returns it.
+/*
+WithNewGoID - creates 'ctx' child, adds a new go id and returns it. If 'ctx' has a go id, it becomes a parent one, so
+goroutines tree can be rebuilt from logs. Once the tree depth limit is reached, 'ctx' is returned as is, so its go id is
+shared by descendants. See WithGoIDDepthLimit().
+*/
 func WithNewGoID(ctx context.Context) context.Context {
+	var parent = GoID(ctx)
+	if parent == "" {
+		return WithGoID(ctx, newID())
+	}
+
+	var depth = GoIDDepth(ctx) + 1
+
+	limit, ok := ctx.Value(keyGoDepthLimit{}).(int)
+	if ok && depth > limit {
+		return ctx
+	}
+
+	ctx = KeyParentGoID.With(ctx, parent)
+	ctx = context.WithValue(ctx, keyGoDepth{}, depth)
+
 	return WithGoID(ctx, newID())
 }
 
+// GoIDDepth - returns a number of go id ancestors. See WithNewGoID().
+func GoIDDepth(ctx context.Context) int {
+	var v, _ = ctx.Value(keyGoDepth{}).(int) //nolint:errcheck
+
+	return v
+}
+
+/*
+WithGoIDDepthLimit - creates 'ctx' child limiting go ids tree depth of its descendants by 'limit' and returns it. See
+WithNewGoID().
+*/
+func WithGoIDDepthLimit(ctx context.Context, limit int) context.Context {
+	return context.WithValue(ctx, keyGoDepthLimit{}, limit)
+}
+
+func ParentGoID(ctx context.Context) string {
+	var v, _ = KeyParentGoID.Get(ctx)
+
+	return v
+}
+
 // WithGoID - creates 'ctx' child, adds respective value and returns it.
 func WithGoID(ctx context.Context, id string) context.Context {
-	return context.WithValue(ctx, keyGoID{}, id)
+	return KeyGoID.With(ctx, id)
 }
 
 func GoID(ctx context.Context) string {
-	var v, _ = ctx.Value(keyGoID{}).(string) //nolint:errcheck
+	var v, _ = KeyGoID.Get(ctx)
 
 	return v
 }
 
-type keyXRequestID struct{}
-
 // WithNewXRequestID - creates 'ctx' child, adds respective value and returns it.
 func WithNewXRequestID(ctx context.Context) context.Context {
 	return WithXRequestID(ctx, newID())
@@ -36,11 +89,11 @@ func WithNewXRequestID(ctx context.Context) context.Context {
 
 // WithXRequestID - creates 'ctx' child, adds respective value and returns it.
 func WithXRequestID(ctx context.Context, id string) context.Context {
-	return context.WithValue(ctx, keyXRequestID{}, id)
+	return KeyXRequestID.With(ctx, id)
 }
 
 func XRequestID(ctx context.Context) string {
-	var v, _ = ctx.Value(keyXRequestID{}).(string) //nolint:errcheck
+	var v, _ = KeyXRequestID.Get(ctx)
 
 	return v
 }
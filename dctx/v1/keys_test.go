@@ -0,0 +1,40 @@
+package dctx_test
+
+import (
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithNewGoID(t *testing.T) {
+	t.Parallel()
+
+	var root = dctx.New()
+	require.NotEmpty(t, dctx.GoID(root))
+	require.Empty(t, dctx.ParentGoID(root))
+	require.Equal(t, 0, dctx.GoIDDepth(root))
+
+	var child = dctx.WithNewGoID(root)
+	require.NotEqual(t, dctx.GoID(root), dctx.GoID(child))
+	require.Equal(t, dctx.GoID(root), dctx.ParentGoID(child))
+	require.Equal(t, 1, dctx.GoIDDepth(child))
+
+	var grandchild = dctx.WithNewGoID(child)
+	require.Equal(t, dctx.GoID(child), dctx.ParentGoID(grandchild))
+	require.Equal(t, 2, dctx.GoIDDepth(grandchild))
+}
+
+func TestWithGoIDDepthLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		root  = dctx.New(dctx.OptionWithGoIDDepthLimit(1))
+		child = dctx.WithNewGoID(root)
+	)
+	require.Equal(t, dctx.GoID(root), dctx.ParentGoID(child))
+
+	// Descendants beyond the limit share the deepest go id.
+	var grandchild = dctx.WithNewGoID(child)
+	require.Equal(t, dctx.GoID(child), dctx.GoID(grandchild))
+	require.Equal(t, dctx.GoID(root), dctx.ParentGoID(grandchild))
+}
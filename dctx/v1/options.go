@@ -2,6 +2,7 @@ package dctx
 
 import (
 	"context"
+	"github.com/don-nv/go-dpkg/dtime/v1"
 	"os"
 	"time"
 )
@@ -31,6 +32,13 @@ func OptionWithNewGoID() Option {
 	return OptionWithGoID(newID())
 }
 
+// OptionWithGoIDDepthLimit - is the same as WithGoIDDepthLimit, but an Option.
+func OptionWithGoIDDepthLimit(limit int) Option {
+	return func(ctx context.Context) context.Context {
+		return WithGoIDDepthLimit(ctx, limit)
+	}
+}
+
 // OptionWithGoID - is the same as WithGoID, but an Option.
 func OptionWithGoID(id string) Option {
 	return func(ctx context.Context) context.Context {
@@ -50,6 +58,16 @@ func OptionWithXRequestID(id string) Option {
 	}
 }
 
+/*
+OptionWithClock - is the same as dtime.WithClock, but an Option. Clock is used by dtime.AwaitDelay, while context
+deadlines and Timeout are still measured by system time.
+*/
+func OptionWithClock(clock dtime.Clock) Option {
+	return func(ctx context.Context) context.Context {
+		return dtime.WithClock(ctx, clock)
+	}
+}
+
 // TTLOption - options context with time-to-live condition.
 type TTLOption func(ctx context.Context) (context.Context, context.CancelFunc)
 
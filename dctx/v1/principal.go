@@ -0,0 +1,90 @@
+package dctx
+
+import (
+	"context"
+	"fmt"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/djson/v1"
+	"slices"
+)
+
+// Principal - is an authenticated caller. Fields are exported to be encoded, see Snapshot().
+type Principal struct {
+	Subject string   `json:"subject"`
+	Tenant  string   `json:"tenant,omitempty"`
+	Roles   []string `json:"roles,omitempty"`
+	Scopes  []string `json:"scopes,omitempty"`
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// keyPrincipal - values are formatted as JSON.
+var keyPrincipal = NewKey[Principal]("principal").WithCodec(
+	func(p Principal) string {
+		data, _ := djson.Marshal(p)
+		return string(data)
+	},
+	func(s string) (Principal, error) {
+		var p Principal
+		return p, djson.Unmarshal([]byte(s), &p)
+	},
+)
+
+// WithPrincipal - creates 'ctx' child, adds 'p' and returns it.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return keyPrincipal.With(ctx, p)
+}
+
+// PrincipalOf - returns 'ctx' Principal. Returned bool is false if there is none.
+func PrincipalOf(ctx context.Context) (Principal, bool) {
+	return keyPrincipal.Get(ctx)
+}
+
+// OptionWithPrincipal - is the same as WithPrincipal, but an Option.
+func OptionWithPrincipal(p Principal) Option {
+	return keyPrincipal.Option(p)
+}
+
+/*
+RequireScope - returns nil if 'ctx' Principal has all 'scopes'.
+
+Errors:
+  - derr.ErrUnauthenticated - 'ctx' has no Principal;
+  - derr.ErrUnauthorized - a scope is missing;
+*/
+func RequireScope(ctx context.Context, scopes ...string) error {
+	p, ok := PrincipalOf(ctx)
+	if !ok {
+		return derr.ErrUnauthenticated
+	}
+
+	for _, scope := range scopes {
+		if !p.HasScope(scope) {
+			return fmt.Errorf("missing %q scope: %w", scope, derr.ErrUnauthorized)
+		}
+	}
+
+	return nil
+}
+
+// RequireRole - is the same as RequireScope, but for roles.
+func RequireRole(ctx context.Context, roles ...string) error {
+	p, ok := PrincipalOf(ctx)
+	if !ok {
+		return derr.ErrUnauthenticated
+	}
+
+	for _, role := range roles {
+		if !p.HasRole(role) {
+			return fmt.Errorf("missing %q role: %w", role, derr.ErrUnauthorized)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package dctx_test
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRequireScope(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, dctx.RequireScope(context.Background(), "orders:read"), derr.ErrUnauthenticated)
+
+	var ctx = dctx.New(dctx.OptionWithPrincipal(dctx.Principal{
+		Subject: "user",
+		Tenant:  "acme",
+		Roles:   []string{"admin"},
+		Scopes:  []string{"orders:read", "orders:write"},
+	}))
+
+	require.NoError(t, dctx.RequireScope(ctx, "orders:read", "orders:write"))
+	require.ErrorIs(t, dctx.RequireScope(ctx, "orders:read", "users:write"), derr.ErrUnauthorized)
+	require.NoError(t, dctx.RequireRole(ctx, "admin"))
+	require.ErrorIs(t, dctx.RequireRole(ctx, "owner"), derr.ErrUnauthorized)
+
+	restored, err := dctx.Restore(context.Background(), dctx.Snapshot(ctx))
+	require.NoError(t, err)
+	require.NoError(t, dctx.RequireScope(restored, "orders:write"))
+}
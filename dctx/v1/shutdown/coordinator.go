@@ -0,0 +1,335 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrForced - is returned by Coordinator.Running() once shutdown is forced and Config.Exit returns.
+var ErrForced = errors.New("shutdown forced")
+
+// Phase - is a shutdown phase. Phases start in the declared order, while PhaseForced may be skipped.
+type Phase int
+
+const (
+	// PhaseRunning - no shutdown is requested yet.
+	PhaseRunning Phase = iota
+	// PhaseDraining - starts on the first signal: service is not ready and stops accepting new work.
+	PhaseDraining
+	// PhaseTerminating - starts once Config.DrainPeriod passes: in-flight work is canceled.
+	PhaseTerminating
+	// PhaseForced - starts on the second signal or once Config.HardTimeout passes: process exits.
+	PhaseForced
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseRunning:
+		return "running"
+
+	case PhaseDraining:
+		return "draining"
+
+	case PhaseTerminating:
+		return "terminating"
+
+	case PhaseForced:
+		return "forced"
+	}
+
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+type Config struct {
+	// DrainPeriod - is a time between PhaseDraining and PhaseTerminating.
+	DrainPeriod time.Duration
+	// HardTimeout - is a time between PhaseDraining and PhaseForced. It is not limited if < 1.
+	HardTimeout time.Duration
+	// Signals (optional) - start shutdown. syscall.SIGTERM and os.Interrupt are used by default.
+	Signals []os.Signal
+	// Exit (optional) - is called with code 1 once shutdown is forced. os.Exit is used by default.
+	Exit func(code int)
+	// Clock (optional) - measures DrainPeriod, HardTimeout and hooks timeouts. dtime.ClockReal is used by default.
+	Clock dtime.Clock
+}
+
+/*
+Hook - is called once its Phase starts. Hooks of a phase are called sequentially in ascending Order, while hooks
+having the same Order are called in registration order. Hook context is done once Timeout passes (unless it is < 1) or
+shutdown is forced. Hook errors are logged.
+*/
+type Hook struct {
+	Phase   Phase
+	Name    string
+	Order   int
+	Timeout time.Duration
+	F       func(ctx context.Context) error
+}
+
+/*
+Coordinator - coordinates a graceful shutdown split into phases, see Phase. Each phase has a context done once the
+phase starts, see Coordinator.Context(). It is intended to be used as follows:
+
+	var (
+		coordinator = shutdown.NewCoordinator(config, log)
+		group       = dsync.NewGroup(coordinator.Context(shutdown.PhaseTerminating))
+	)
+
+	go coordinator.Running(ctx)
+	defer coordinator.Finish()
+
+	group.Go(func(context.Context) error {
+		return server.Running(coordinator.Context(shutdown.PhaseDraining))
+	})
+
+	return group.Wait()
+
+dhttp.Server stops accepting requests once PhaseDraining starts, while its in-flight requests are canceled once
+PhaseTerminating starts if server is created with dhttp.OptionServerWithBaseContext(). It is safe to be used
+concurrently.
+*/
+type Coordinator struct {
+	config Config
+	log    dlog.Logger
+
+	mu      sync.Mutex
+	phase   Phase
+	hooks   []Hook
+	cancels map[Phase]context.CancelFunc
+	ctxs    map[Phase]context.Context
+
+	triggerC chan struct{}
+	trigger  sync.Once
+	finishC  chan struct{}
+	finish   sync.Once
+}
+
+func NewCoordinator(config Config, log dlog.Logger) *Coordinator {
+	if len(config.Signals) == 0 {
+		config.Signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+
+	if config.Exit == nil {
+		config.Exit = os.Exit
+	}
+
+	if config.Clock == nil {
+		config.Clock = dtime.ClockReal
+	}
+
+	var c = &Coordinator{
+		config:   config,
+		log:      log.With().Name("shutdown").Build(),
+		cancels:  make(map[Phase]context.CancelFunc),
+		ctxs:     make(map[Phase]context.Context),
+		triggerC: make(chan struct{}),
+		finishC:  make(chan struct{}),
+	}
+
+	for _, phase := range []Phase{PhaseDraining, PhaseTerminating, PhaseForced} {
+		c.ctxs[phase], c.cancels[phase] = context.WithCancel(context.Background())
+	}
+
+	return c
+}
+
+/*
+Context - returns a context done once 'phase' starts. Phases start in order, so contexts of the previous phases are
+done as well. PhaseRunning context is never done.
+*/
+func (c *Coordinator) Context(phase Phase) context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, ok := c.ctxs[phase]
+	if !ok {
+		return context.Background()
+	}
+
+	return ctx
+}
+
+func (c *Coordinator) Phase() Phase {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.phase
+}
+
+// Ready - reports if shutdown is not started yet. It is intended to be used by readiness probes.
+func (c *Coordinator) Ready() bool {
+	return c.Phase() == PhaseRunning
+}
+
+// Hook - registers 'hook'. Hooks registered after their phase started are not called.
+func (c *Coordinator) Hook(hook Hook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = append(c.hooks, hook)
+}
+
+// Shutdown - starts shutdown as if the first signal was received.
+func (c *Coordinator) Shutdown() {
+	c.trigger.Do(func() { close(c.triggerC) })
+}
+
+// Finish - reports that all work is done, so Coordinator.Running() returns without forcing shutdown.
+func (c *Coordinator) Finish() {
+	c.finish.Do(func() { close(c.finishC) })
+}
+
+/*
+Running - awaits shutdown start, which is the first signal, Coordinator.Shutdown() call or 'ctx' done, and then runs
+phases: PhaseTerminating starts once Config.DrainPeriod passes or Coordinator.Finish() is called. It returns once both
+Coordinator.Finish() is called and PhaseTerminating hooks are done, while joined errors of hooks are returned. If the
+second signal is received or Config.HardTimeout passes meanwhile, shutdown is forced: PhaseForced hooks are called and
+Config.Exit is called with code 1.
+*/
+func (c *Coordinator) Running(ctx context.Context) error {
+	var signals = make(chan os.Signal, 2)
+	signal.Notify(signals, c.config.Signals...)
+	defer signal.Stop(signals)
+
+	select {
+	case <-ctx.Done():
+	case <-c.triggerC:
+	case <-c.finishC:
+		return nil
+
+	case s := <-signals:
+		c.log.I().Any("signal", s.String()).Write("received shutdown signal")
+	}
+
+	var hardC <-chan time.Time
+	if c.config.HardTimeout > 0 {
+		hardC = c.config.Clock.After(c.config.HardTimeout)
+	}
+
+	// Hooks are called apart, so shutdown may be forced meanwhile.
+	var phasesC = make(chan []error, 1)
+	go func() {
+		var errs = c.start(PhaseDraining)
+
+		select {
+		case <-c.config.Clock.After(c.config.DrainPeriod):
+		case <-c.finishC:
+		}
+
+		phasesC <- append(errs, c.start(PhaseTerminating)...)
+	}()
+
+	var (
+		finishC = c.finishC
+		errs    []error
+		done    bool
+	)
+
+	for {
+		select {
+		case errs = <-phasesC:
+			done = true
+
+		case <-finishC:
+			finishC = nil
+
+		case s := <-signals:
+			c.log.W().Any("signal", s.String()).Write("received second shutdown signal")
+
+			return c.force()
+
+		case <-hardC:
+			c.log.W().Any("hard_timeout", c.config.HardTimeout.String()).Write("shutdown hard timeout passed")
+
+			return c.force()
+		}
+
+		if done && finishC == nil {
+			c.log.I().Write("shutdown finished")
+
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// force - starts PhaseForced, which cancels all phases contexts, and exits.
+func (c *Coordinator) force() error {
+	c.cancels[PhaseDraining]()
+	c.cancels[PhaseTerminating]()
+
+	_ = c.start(PhaseForced)
+
+	c.config.Exit(1)
+
+	return ErrForced
+}
+
+// start - starts 'phase' canceling its context and calling its hooks. Returns hooks errors.
+func (c *Coordinator) start(phase Phase) []error {
+	c.mu.Lock()
+	if phase != PhaseForced {
+		c.phase = phase
+	}
+
+	var hooks []Hook
+	for _, hook := range c.hooks {
+		if hook.Phase == phase {
+			hooks = append(hooks, hook)
+		}
+	}
+	c.mu.Unlock()
+
+	c.log.I().Any("phase", phase.String()).Write("shutdown phase started")
+
+	c.cancels[phase]()
+
+	slices.SortStableFunc(hooks, func(a, b Hook) int {
+		return a.Order - b.Order
+	})
+
+	var errs []error
+	for _, hook := range hooks {
+		err := c.callHook(hook)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q hook: %w", hook.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func (c *Coordinator) callHook(hook Hook) error {
+	var ctx, cancel = context.WithCancel(c.Context(PhaseForced))
+	defer cancel()
+
+	if hook.Timeout > 0 {
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-c.config.Clock.After(hook.Timeout):
+				cancel()
+			}
+		}()
+	}
+
+	var log = c.log.With().Any("phase", hook.Phase.String()).Any("hook", hook.Name).Build()
+
+	err := hook.F(ctx)
+	if err != nil {
+		log.E().Writef("hook failed: %s", err)
+		return err
+	}
+
+	log.D().Write("hook done")
+
+	return nil
+}
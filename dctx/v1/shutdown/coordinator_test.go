@@ -0,0 +1,111 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"github.com/don-nv/go-dpkg/dctx/v1/shutdown"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/stretchr/testify/require"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCoordinator_Running(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+		c     = shutdown.NewCoordinator(shutdown.Config{DrainPeriod: 50 * time.Millisecond}, dlog.New())
+	)
+
+	var hook = func(phase shutdown.Phase, name string, order int, err error) {
+		c.Hook(shutdown.Hook{
+			Phase: phase,
+			Name:  name,
+			Order: order,
+			F: func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				calls = append(calls, name)
+
+				return err
+			},
+		})
+	}
+
+	hook(shutdown.PhaseTerminating, "close_db", 2, nil)
+	hook(shutdown.PhaseTerminating, "flush", 1, errors.New("flush failed"))
+	hook(shutdown.PhaseDraining, "unregister", 0, nil)
+	hook(shutdown.PhaseForced, "forced", 0, nil)
+
+	var errC = make(chan error, 1)
+	go func() {
+		errC <- c.Running(context.Background())
+	}()
+
+	require.True(t, c.Ready())
+
+	c.Shutdown()
+
+	<-c.Context(shutdown.PhaseDraining).Done()
+	require.False(t, c.Ready())
+	require.NoError(t, c.Context(shutdown.PhaseTerminating).Err())
+
+	<-c.Context(shutdown.PhaseTerminating).Done()
+	require.NoError(t, c.Context(shutdown.PhaseForced).Err())
+
+	c.Finish()
+
+	err := <-errC
+	require.ErrorContains(t, err, "flush failed")
+	require.Equal(t, []string{"unregister", "flush", "close_db"}, calls)
+	require.Equal(t, shutdown.PhaseTerminating, c.Phase())
+}
+
+func TestCoordinator_Forced(t *testing.T) {
+	var (
+		exitC = make(chan int, 1)
+		c     = shutdown.NewCoordinator(
+			shutdown.Config{
+				DrainPeriod: time.Hour,
+				HardTimeout: time.Hour,
+				Signals:     []os.Signal{syscall.SIGUSR1},
+				Exit:        func(code int) { exitC <- code },
+			},
+			dlog.New(),
+		)
+		hookCtxErr = make(chan error, 1)
+	)
+
+	c.Hook(shutdown.Hook{
+		Phase:   shutdown.PhaseDraining,
+		Name:    "slow",
+		Timeout: 20 * time.Millisecond,
+		F: func(ctx context.Context) error {
+			<-ctx.Done()
+			hookCtxErr <- ctx.Err()
+
+			return nil
+		},
+	})
+
+	var errC = make(chan error, 1)
+	go func() {
+		errC <- c.Running(context.Background())
+	}()
+
+	c.Shutdown()
+
+	// Signals are being received once draining starts.
+	<-c.Context(shutdown.PhaseDraining).Done()
+	require.ErrorIs(t, <-hookCtxErr, context.Canceled)
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	require.ErrorIs(t, <-errC, shutdown.ErrForced)
+	require.Equal(t, 1, <-exitC)
+	require.Error(t, c.Context(shutdown.PhaseTerminating).Err())
+	require.Error(t, c.Context(shutdown.PhaseForced).Err())
+}
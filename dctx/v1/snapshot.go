@@ -0,0 +1,57 @@
+package dctx
+
+import (
+	"context"
+	"errors"
+)
+
+/*
+Snapshot - returns values of each registered key found in 'ctx' formatted by the key codec and indexed by the key
+name, see NewKey(). Cancellation and deadline are not captured. Snapshot is intended to be encoded, e.g. to JSON, and to
+be restored by Restore() once work is resumed.
+*/
+func Snapshot(ctx context.Context) map[string]string {
+	var snapshot = make(map[string]string)
+
+	RangeKeys(func(k AnyKey) bool {
+		v, ok := k.Format(ctx)
+		if ok {
+			snapshot[k.Name()] = v
+		}
+
+		return true
+	})
+
+	return snapshot
+}
+
+/*
+Restore - creates 'parent' child having 'snapshot' values of registered keys, see Snapshot(). Values of unknown keys
+are skipped, while values failed to be parsed are skipped and their errors are returned joined. Restored trace is a
+child span of the captured one.
+*/
+func Restore(parent context.Context, snapshot map[string]string) (context.Context, error) {
+	var (
+		ctx  = parent
+		errs []error
+	)
+
+	RangeKeys(func(k AnyKey) bool {
+		v, ok := snapshot[k.Name()]
+		if !ok {
+			return true
+		}
+
+		restored, err := k.WithParsed(ctx, v)
+		if err != nil {
+			errs = append(errs, err)
+			return true
+		}
+
+		ctx = restored
+
+		return true
+	})
+
+	return ctx, errors.Join(errs...)
+}
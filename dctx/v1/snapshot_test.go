@@ -0,0 +1,58 @@
+package dctx_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var trace = dctx.NewTrace()
+	trace.State = "vendor=value"
+
+	ctx, cancel := dctx.NewTTL(dctx.OptionTTLWithTimeout(time.Minute))
+	defer cancel()
+
+	ctx = dctx.WithOptions(ctx,
+		dctx.OptionWithNewXRequestID(),
+		dctx.OptionWithTrace(trace),
+		keyTestVersion.Option(7),
+	)
+
+	data, err := json.Marshal(dctx.Snapshot(ctx))
+	require.NoError(t, err)
+
+	var snapshot map[string]string
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+
+	snapshot["unknown"] = "skipped"
+
+	restored, err := dctx.Restore(context.Background(), snapshot)
+	require.NoError(t, err)
+
+	_, ok := restored.Deadline()
+	require.False(t, ok)
+
+	cancel()
+	require.NoError(t, restored.Err())
+
+	require.Equal(t, dctx.GoID(ctx), dctx.GoID(restored))
+	require.Equal(t, dctx.XRequestID(ctx), dctx.XRequestID(restored))
+
+	version, _ := keyTestVersion.Get(restored)
+	require.Equal(t, 7, version)
+
+	restoredTrace, ok := dctx.TraceOf(restored)
+	require.True(t, ok)
+	require.Equal(t, trace.TraceID, restoredTrace.TraceID)
+	require.Equal(t, trace.SpanID, restoredTrace.ParentSpanID)
+	require.Equal(t, trace.State, restoredTrace.State)
+
+	_, err = dctx.Restore(context.Background(), map[string]string{"test_version": "invalid"})
+	require.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package dctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// TraceFlagSampled - is a W3C Trace Context "sampled" flag.
+	TraceFlagSampled byte = 0x01
+
+	traceIDLen = 32
+	spanIDLen  = 16
+	// traceparentVersion - is the only W3C Trace Context version supported.
+	traceparentVersion = "00"
+)
+
+/*
+Trace - is a W3C Trace Context of a current operation, see https://www.w3.org/TR/trace-context/. IDs are lowercase hex
+strings.
+*/
+type Trace struct {
+	// TraceID - is shared by all spans of a trace.
+	TraceID string
+	// SpanID - identifies a current operation.
+	SpanID string
+	// ParentSpanID - identifies an operation current one was called by. It is empty for a root span.
+	ParentSpanID string
+	Flags        byte
+	// State - is a vendor specific "tracestate" propagated as is.
+	State string
+}
+
+// NewTrace - returns a root span of a new sampled trace.
+func NewTrace() Trace {
+	return Trace{
+		TraceID: newTraceID(traceIDLen),
+		SpanID:  newTraceID(spanIDLen),
+		Flags:   TraceFlagSampled,
+	}
+}
+
+// Child - returns a new span of the same trace called by 't' span.
+func (t Trace) Child() Trace {
+	t.ParentSpanID = t.SpanID
+	t.SpanID = newTraceID(spanIDLen)
+
+	return t
+}
+
+func (t Trace) Sampled() bool {
+	return t.Flags&TraceFlagSampled != 0
+}
+
+// Traceparent - formats "traceparent" header value of 't' span.
+func (t Trace) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, t.TraceID, t.SpanID, t.Flags)
+}
+
+/*
+ParseTraceparent - parses "traceparent" header value and returns a span having the parsed one as a parent. 'state' is
+"tracestate" header value, it may be empty.
+*/
+func ParseTraceparent(traceparent, state string) (Trace, error) {
+	var parts = strings.Split(strings.TrimSpace(traceparent), "-")
+
+	const partsN = 4
+	if len(parts) < partsN {
+		return Trace{}, fmt.Errorf("invalid traceparent parts, %d < %d", len(parts), partsN)
+	}
+
+	var version, traceID, parentID, flags = parts[0], parts[1], parts[2], parts[3]
+
+	if version == "ff" || !isTraceHex(version, 2) {
+		return Trace{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	// Future versions may append parts, while the current one must not.
+	if version == traceparentVersion && len(parts) != partsN {
+		return Trace{}, fmt.Errorf("invalid traceparent parts, %d != %d", len(parts), partsN)
+	}
+
+	if !isTraceHex(traceID, traceIDLen) || isTraceZero(traceID) {
+		return Trace{}, errors.New("invalid trace id")
+	}
+
+	if !isTraceHex(parentID, spanIDLen) || isTraceZero(parentID) {
+		return Trace{}, errors.New("invalid parent id")
+	}
+
+	if !isTraceHex(flags, 2) {
+		return Trace{}, fmt.Errorf("invalid trace flags %q", flags)
+	}
+
+	flagsB, _ := hex.DecodeString(flags)
+
+	var parent = Trace{
+		TraceID: traceID,
+		SpanID:  parentID,
+		Flags:   flagsB[0],
+		State:   strings.TrimSpace(state),
+	}
+
+	return parent.Child(), nil
+}
+
+// keyTrace - values are formatted as "traceparent;tracestate", while parsed ones are child spans of formatted ones.
+var keyTrace = NewKey[Trace]("trace").WithCodec(
+	func(t Trace) string {
+		return t.Traceparent() + ";" + t.State
+	},
+	func(s string) (Trace, error) {
+		traceparent, state, _ := strings.Cut(s, ";")
+		return ParseTraceparent(traceparent, state)
+	},
+)
+
+// WithTrace - creates 'ctx' child, adds 't' and returns it.
+func WithTrace(ctx context.Context, t Trace) context.Context {
+	return keyTrace.With(ctx, t)
+}
+
+// TraceOf - returns 'ctx' Trace. Returned bool is false if there is none.
+func TraceOf(ctx context.Context) (Trace, bool) {
+	return keyTrace.Get(ctx)
+}
+
+// OptionWithTrace - is the same as WithTrace, but an Option.
+func OptionWithTrace(t Trace) Option {
+	return keyTrace.Option(t)
+}
+
+// OptionWithNewTrace - is the same as WithTrace with NewTrace, but an Option.
+func OptionWithNewTrace() Option {
+	return func(ctx context.Context) context.Context {
+		return WithTrace(ctx, NewTrace())
+	}
+}
+
+func newTraceID(n int) string {
+	var b = make([]byte, n/2)
+
+	for {
+		_, _ = rand.Read(b)
+
+		var id = hex.EncodeToString(b)
+		if !isTraceZero(id) {
+			return id
+		}
+	}
+}
+
+// isTraceHex - reports if 's' consists of 'n' lowercase hex digits.
+func isTraceHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isTraceZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
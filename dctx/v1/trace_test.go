@@ -0,0 +1,47 @@
+package dctx_test
+
+import (
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	trace, err := dctx.ParseTraceparent(traceparent, "congo=t61rcWkgMzE")
+	require.NoError(t, err)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.TraceID)
+	require.Equal(t, "00f067aa0ba902b7", trace.ParentSpanID)
+	require.NotEqual(t, trace.ParentSpanID, trace.SpanID)
+	require.Len(t, trace.SpanID, 16)
+	require.True(t, trace.Sampled())
+	require.Equal(t, "congo=t61rcWkgMzE", trace.State)
+
+	var child = trace.Child()
+	require.Equal(t, trace.TraceID, child.TraceID)
+	require.Equal(t, trace.SpanID, child.ParentSpanID)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+child.SpanID+"-01", child.Traceparent())
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	} {
+		_, err = dctx.ParseTraceparent(invalid, "")
+		require.Error(t, err, invalid)
+	}
+
+	// Future versions may have additional parts.
+	_, err = dctx.ParseTraceparent(traceparent[2:]+"-extra", "")
+	require.Error(t, err)
+	_, err = dctx.ParseTraceparent("01"+traceparent[2:]+"-extra", "")
+	require.NoError(t, err)
+}
@@ -0,0 +1,165 @@
+package derr
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// errorStackDepthMax - is a max number of frames recorded by New().
+const errorStackDepthMax = 32
+
+/*
+Error - is an application error carrying:
+  - code - is a machine readable code, e.g. "order_not_found";
+  - HTTP status code - see Error.Status();
+  - public message - is safe to be shown to clients, see Error.Msg();
+  - fields - are private details, which are logged, but not shown to clients, see Error.With();
+  - cause - is a wrapped error, see Error.Wrap();
+  - stack - is recorded by New() and by each builder method, see Error.Frames();
+
+Builder methods return a modified copy recording a stack of their caller, so an Error may be declared as a package
+variable and be used as a sentinel: errors.Is() reports if errors have the same code, while copies built for actual
+occurrences, e.g. by Error.Wrap(), report stacks of those occurrences.
+*/
+type Error struct {
+	code    string
+	status  int
+	msg     string
+	fields  []Field
+	cause   error
+	callers []uintptr
+}
+
+// Field - is an Error private detail.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// New - returns Error having 'code' and a stack of a New() caller.
+func New(code string) *Error {
+	return &Error{
+		code:    code,
+		callers: errorCallers(1),
+	}
+}
+
+// Msg - sets a public message.
+func (e *Error) Msg(msg string) *Error {
+	var c = e.copy()
+	c.msg = msg
+
+	return c
+}
+
+// Msgf - is the same as Error.Msg(), but formats a message.
+func (e *Error) Msgf(format string, args ...any) *Error {
+	var c = e.copy()
+	c.msg = fmt.Sprintf(format, args...)
+
+	return c
+}
+
+// Status - sets HTTP status code. See Error.HTTPStatus().
+func (e *Error) Status(code int) *Error {
+	var c = e.copy()
+	c.status = code
+
+	return c
+}
+
+// With - appends a private detail.
+func (e *Error) With(key string, value any) *Error {
+	var c = e.copy()
+	c.fields = append(slices.Clip(e.fields), Field{Key: key, Value: value})
+
+	return c
+}
+
+// Wrap - sets a cause, which is returned by Error.Unwrap().
+func (e *Error) Wrap(err error) *Error {
+	var c = e.copy()
+	c.cause = err
+
+	return c
+}
+
+// Error - returns code, public message and cause joined by ": ". Private details are omitted.
+func (e *Error) Error() string {
+	var parts = make([]string, 0, 3)
+
+	parts = append(parts, e.code)
+	if e.msg != "" {
+		parts = append(parts, e.msg)
+	}
+
+	if e.cause != nil {
+		parts = append(parts, e.cause.Error())
+	}
+
+	return strings.Join(parts, ": ")
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is - reports if 'target' is an Error having the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && t.code == e.code
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+// HTTPStatus - returns HTTP status code or 0 if it is not set.
+func (e *Error) HTTPStatus() int {
+	return e.status
+}
+
+// Message - returns public message.
+func (e *Error) Message() string {
+	return e.msg
+}
+
+// Fields - returns private details in order they were added.
+func (e *Error) Fields() []Field {
+	return slices.Clone(e.fields)
+}
+
+// Frames - returns stack recorded by New() or by the last builder method, the most recent call first.
+func (e *Error) Frames() []runtime.Frame {
+	var (
+		frames = runtime.CallersFrames(e.callers)
+		result = make([]runtime.Frame, 0, len(e.callers))
+	)
+
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+
+		if !more {
+			return result
+		}
+	}
+}
+
+// copy - returns a copy of 'e' having a stack of a builder method caller. It must be called by builder methods only.
+func (e *Error) copy() *Error {
+	var c = *e
+	c.callers = errorCallers(2)
+
+	return &c
+}
+
+// errorCallers - returns a stack of errorCallers() caller skipping 'skip' more frames.
+func errorCallers(skip int) []uintptr {
+	var callers = make([]uintptr, errorStackDepthMax)
+
+	// Skips runtime.Callers() and errorCallers() as well.
+	return callers[:runtime.Callers(skip+2, callers)]
+}
@@ -0,0 +1,56 @@
+package derr_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"runtime"
+	"testing"
+)
+
+var errNotFound = derr.New("not_found").Status(http.StatusNotFound)
+
+func TestError(t *testing.T) {
+	var (
+		cause = errors.New("no rows")
+		err   = fmt.Errorf("getting order: %w", errNotFound.Msg("order is not found").With("id", 1).Wrap(cause))
+	)
+
+	require.ErrorIs(t, err, errNotFound)
+	require.ErrorIs(t, err, cause)
+	require.NotErrorIs(t, err, derr.New("other"))
+	require.NotErrorIs(t, errNotFound, err, "target wrapping an Error is not the Error")
+	require.NotErrorIs(t, err, (*derr.Error)(nil))
+	require.Equal(t, "getting order: not_found: order is not found: no rows", err.Error())
+
+	var derror *derr.Error
+	require.ErrorAs(t, err, &derror)
+	require.Equal(t, "not_found", derror.Code())
+	require.Equal(t, http.StatusNotFound, derror.HTTPStatus())
+	require.Equal(t, "order is not found", derror.Message())
+	require.Equal(t, []derr.Field{{Key: "id", Value: 1}}, derror.Fields())
+	require.NotEmpty(t, derror.Frames())
+
+	t.Log("builder methods do not modify a receiver")
+	require.Empty(t, errNotFound.Message())
+	require.Empty(t, errNotFound.Fields())
+	require.NoError(t, errNotFound.Unwrap())
+}
+
+func TestError_Frames(t *testing.T) {
+	var stacks = map[string][]runtime.Frame{
+		"new":     derr.New("code").Frames(),
+		"wrapped": errNotFound.Wrap(errors.New("cause")).Frames(),
+		"msg":     errNotFound.Msg("msg").Frames(),
+		"msgf":    errNotFound.Msgf("%s", "msg").Frames(),
+		"with":    errNotFound.With("k", "v").Frames(),
+		"status":  errNotFound.Status(http.StatusGone).Frames(),
+	}
+
+	for name, frames := range stacks {
+		require.NotEmpty(t, frames, name)
+		require.Equal(t, "github.com/don-nv/go-dpkg/derr/v1_test.TestError_Frames", frames[0].Function, name)
+	}
+}
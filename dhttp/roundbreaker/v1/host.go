@@ -4,6 +4,7 @@ import (
 	"github.com/don-nv/go-dpkg/djson/v1"
 	"github.com/don-nv/go-dpkg/dstruct/v1"
 	"github.com/don-nv/go-dpkg/dsync/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
 	"github.com/sethvargo/go-envconfig"
 	"gopkg.in/yaml.v3"
 	"time"
@@ -40,6 +41,8 @@ type Host struct {
 	Scheme      string
 	HostPort    string
 	DisabledFor time.Duration
+	// Clock (optional) - measures DisabledFor. dtime.ClockReal is used by default.
+	Clock dtime.Clock
 }
 
 func (h *Host) disableFor() {
@@ -47,7 +50,7 @@ func (h *Host) disableFor() {
 		// Prevent multiple enabling.
 		if !h.disabled && h.Attempts.Exceeded() {
 			go func() {
-				<-time.After(h.DisabledFor)
+				<-h.clock().After(h.DisabledFor)
 
 				h.enable()
 			}()
@@ -57,6 +60,14 @@ func (h *Host) disableFor() {
 	})
 }
 
+func (h *Host) clock() dtime.Clock {
+	if h.Clock == nil {
+		return dtime.ClockReal
+	}
+
+	return h.Clock
+}
+
 func (h *Host) enable() {
 	h.mu.LockF(func() {
 		h.disabled = false
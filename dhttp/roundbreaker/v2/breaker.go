@@ -123,7 +123,7 @@ func (b Breaker) sendOverHosts(req *http.Request, getBody func() io.ReadCloser)
 			resp         *http.Response
 			reqStartedAt = time.Now()
 		)
-		err = host.attempt(func() error {
+		err = host.attempt(b.config.clock(), func() error {
 			req.URL.Scheme = host.scheme
 			req.URL.Host = host.hostPort
 			req.Body = getBody()
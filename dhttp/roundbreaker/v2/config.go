@@ -3,6 +3,7 @@ package roundbreaker
 import (
 	"errors"
 	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
 	"time"
 )
 
@@ -16,6 +17,16 @@ type Config struct {
 		(if exists) is logged despite any response log configuration.
 	*/
 	Logger dhttp.LoggerConfig
+	// Clock (optional) - measures hosts HostConfig.DisabledFor. dtime.ClockReal is used by default.
+	Clock dtime.Clock
+}
+
+func (c Config) clock() dtime.Clock {
+	if c.Clock == nil {
+		return dtime.ClockReal
+	}
+
+	return c.Clock
 }
 
 func (c Config) validate() error {
@@ -7,6 +7,7 @@ import (
 	"github.com/don-nv/go-dpkg/djson/v1"
 	"github.com/don-nv/go-dpkg/dstruct/v1"
 	"github.com/don-nv/go-dpkg/dsync/v1"
+	"github.com/don-nv/go-dpkg/dtime/v1"
 	"github.com/sethvargo/go-envconfig"
 	"gopkg.in/yaml.v3"
 	"time"
@@ -53,7 +54,7 @@ Errors:
   - derr.ErrDisabled
   - derr.ErrExceeded
 */
-func (h *Host) attempt(f func() error) error {
+func (h *Host) attempt(clock dtime.Clock, f func() error) error {
 	if !h.enabled() {
 		return derr.ErrDisabled
 	}
@@ -62,7 +63,7 @@ func (h *Host) attempt(f func() error) error {
 	if err != nil {
 		// Inc attempt.
 		if !h.attempts.Next() {
-			h.disableFor()
+			h.disableFor(clock)
 
 			return derr.Join(err, derr.ErrExceeded)
 		}
@@ -86,10 +87,10 @@ func (h *Host) attempt(f func() error) error {
 }
 
 /*
-disableFor - disables enabled host for 'disabledFor' duration or until 'enableNow' call. Enabling is done in
-a separate goroutine.
+disableFor - disables enabled host for 'disabledFor' duration measured by 'clock' or until 'enableNow' call.
+Enabling is done in a separate goroutine.
 */
-func (h *Host) disableFor() {
+func (h *Host) disableFor(clock dtime.Clock) {
 	h.mu.LockF(func() {
 		// Prevent multiple enabling.
 		if h.enabledUnsafe() {
@@ -100,7 +101,7 @@ func (h *Host) disableFor() {
 			go func() {
 				select {
 				case <-ctx.Done():
-				case <-time.After(h.disabledFor):
+				case <-clock.After(h.disabledFor):
 				}
 
 				h.enable()
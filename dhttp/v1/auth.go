@@ -0,0 +1,110 @@
+package dhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"net/http"
+)
+
+/*
+Authenticator - authenticates a request caller. Returned error is expected to wrap derr.ErrUnauthenticated if caller
+is not authenticated or derr.ErrUnauthorized if caller is not allowed to send requests at all. See AuthErrorStatus().
+*/
+type Authenticator interface {
+	Authenticate(req *http.Request) (dctx.Principal, error)
+}
+
+// AuthenticatorFunc - is an Authenticator function.
+type AuthenticatorFunc func(req *http.Request) (dctx.Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) (dctx.Principal, error) {
+	return f(req)
+}
+
+/*
+NewBearerAuthenticator - returns an Authenticator looking up a token by HeaderBearerTokenGet() and passing it to
+'verify'. Missing or malformed token is derr.ErrUnauthenticated.
+*/
+func NewBearerAuthenticator(verify func(ctx context.Context, token string) (dctx.Principal, error)) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) (dctx.Principal, error) {
+		token, err := HeaderBearerTokenGet(req.Header)
+		if err != nil {
+			return dctx.Principal{}, errors.Join(fmt.Errorf("getting bearer token: %w", err), derr.ErrUnauthenticated)
+		}
+
+		return verify(req.Context(), token)
+	})
+}
+
+/*
+AuthErrorStatus - returns HTTP status code and CodeError respective to 'err':
+  - derr.ErrUnauthorized - 403 Forbidden and Code403AccessForbidden;
+  - derr.ErrUnauthenticated - 401 Unauthorized and Code401General;
+  - other - 500 Internal Server Error and Code500General;
+*/
+func AuthErrorStatus(err error) (int, CodeError) {
+	switch {
+	case errors.Is(err, derr.ErrUnauthorized):
+		return http.StatusForbidden, Code403AccessForbidden
+
+	case errors.Is(err, derr.ErrUnauthenticated):
+		return http.StatusUnauthorized, Code401General
+	}
+
+	return http.StatusInternalServerError, Code500General
+}
+
+/*
+OptionHandlerWithAuthenticator - authenticates requests by 'a' and populates their context with dctx.WithPrincipal().
+Requests failed to be authenticated are replied with AuthErrorStatus(), while other failures are logged.
+*/
+func OptionHandlerWithAuthenticator(a Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(resp http.ResponseWriter, req *http.Request) {
+				principal, err := a.Authenticate(req)
+				if err != nil {
+					writeAuthError(resp, req, err)
+					return
+				}
+
+				req = req.WithContext(dctx.WithPrincipal(req.Context(), principal))
+
+				next.ServeHTTP(resp, req)
+			},
+		)
+	}
+}
+
+/*
+OptionHandlerWithScopes - replies requests which context lacks 'scopes' with AuthErrorStatus(). See
+dctx.RequireScope().
+*/
+func OptionHandlerWithScopes(scopes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(resp http.ResponseWriter, req *http.Request) {
+				err := dctx.RequireScope(req.Context(), scopes...)
+				if err != nil {
+					writeAuthError(resp, req, err)
+					return
+				}
+
+				next.ServeHTTP(resp, req)
+			},
+		)
+	}
+}
+
+func writeAuthError(resp http.ResponseWriter, req *http.Request, err error) {
+	statusCode, code := AuthErrorStatus(err)
+	if statusCode == http.StatusInternalServerError {
+		dlog.E().Scope(req.Context()).Writef("authenticating: %s", err)
+	}
+
+	writeResponseError(resp, req, statusCode, ResponseError{Code: code})
+}
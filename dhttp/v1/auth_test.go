@@ -0,0 +1,67 @@
+package dhttp_test
+
+import (
+	"context"
+	"errors"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOptionHandlerWithAuthenticator(t *testing.T) {
+	var authenticator = dhttp.NewBearerAuthenticator(
+		func(_ context.Context, token string) (dctx.Principal, error) {
+			switch token {
+			case "writer":
+				return dctx.Principal{Subject: "writer", Scopes: []string{"orders:write"}}, nil
+
+			case "reader":
+				return dctx.Principal{Subject: "reader", Scopes: []string{"orders:read"}}, nil
+
+			case "banned":
+				return dctx.Principal{}, derr.ErrUnauthorized
+
+			case "failing":
+				return dctx.Principal{}, errors.New("identity provider is down")
+			}
+
+			return dctx.Principal{}, derr.ErrUnauthenticated
+		},
+	)
+
+	var (
+		subject string
+		handler = dhttp.OptionHandlerWithAuthenticator(authenticator)(
+			dhttp.OptionHandlerWithScopes("orders:write")(
+				http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+					principal, _ := dctx.PrincipalOf(req.Context())
+					subject = principal.Subject
+				}),
+			),
+		)
+	)
+
+	for authorization, statusCode := range map[string]int{
+		"Bearer writer":  http.StatusOK,
+		"Bearer reader":  http.StatusForbidden,
+		"Bearer banned":  http.StatusForbidden,
+		"Bearer unknown": http.StatusUnauthorized,
+		"Bearer failing": http.StatusInternalServerError,
+		"Basic writer":   http.StatusUnauthorized,
+		"":               http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(dhttp.HeaderKeyAuthorization, authorization)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, statusCode, rec.Code, authorization)
+	}
+
+	require.Equal(t, "writer", subject)
+}
@@ -0,0 +1,134 @@
+package dhttp
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HeaderKeyRequestTimeout - is a default header carrying remaining request time budget in milliseconds.
+const HeaderKeyRequestTimeout = "X-Request-Timeout"
+
+// budgetMsMax - is the max budget in milliseconds, which does not overflow time.Duration. Larger budgets are capped.
+const budgetMsMax = math.MaxInt64 / int64(time.Millisecond)
+
+/*
+OptionRequestHeaderWithTimeout - is used to populate request 'header' (HeaderKeyRequestTimeout if empty) with remaining
+dctx.Timeout() of request context in milliseconds minus 'margin', which is a time reserved to receive a response. No
+header is set if context has no deadline. Budget is rounded up to milliseconds. Once budget is expired, "0" is set and
+a warning is logged.
+*/
+func OptionRequestHeaderWithTimeout(header string, margin time.Duration) RequestOption {
+	if header == "" {
+		header = HeaderKeyRequestTimeout
+	}
+
+	return func(req *http.Request) *http.Request {
+		var ctx = req.Context()
+
+		timeout := dctx.Timeout(ctx)
+		if timeout == 0 {
+			return req
+		}
+
+		var budget = timeout - margin
+		if budget <= 0 {
+			budget = 0
+
+			dlog.New().W().
+				Scope(ctx).
+				Any("url", req.URL.String()).
+				Any("timeout", timeout.String()).
+				Any("margin", margin.String()).
+				Write("sending request having expired time budget")
+		}
+
+		// Budget is rounded up, so a budget below a millisecond is not considered to be expired by a receiver.
+		var ms = (budget + time.Millisecond - 1) / time.Millisecond
+
+		req.Header.Set(header, strconv.FormatInt(int64(ms), 10))
+
+		return req
+	}
+}
+
+/*
+RequestTimeoutBudget - returns time budget in milliseconds found in 'req' 'header' (HeaderKeyRequestTimeout if empty).
+Returned bool is false if header is missing or invalid. Budget is capped by the max time.Duration.
+*/
+func RequestTimeoutBudget(req *http.Request, header string) (time.Duration, bool) {
+	if header == "" {
+		header = HeaderKeyRequestTimeout
+	}
+
+	ms, err := strconv.ParseInt(req.Header.Get(header), 10, 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+
+	return time.Duration(min(ms, budgetMsMax)) * time.Millisecond, true
+}
+
+/*
+RequestContextWithTimeoutBudget - sets request context ttl to a budget found in 'header' (see RequestTimeoutBudget())
+capped by 'maxTTL', which is a server policy. 'maxTTL' is applied if there is no budget, while 'maxTTL' < 1 means no
+cap. Returned bool is false if budget is expired, in that case a warning is logged and context is already done.
+Returned request is a shallow copy.
+*/
+func RequestContextWithTimeoutBudget(
+	req *http.Request, header string, maxTTL time.Duration,
+) (
+	*http.Request, context.CancelFunc, bool,
+) {
+	budget, ok := RequestTimeoutBudget(req, header)
+
+	switch {
+	case ok && budget == 0:
+		dlog.New().W().
+			Scope(req.Context()).
+			Any("path", req.URL.Path).
+			Write("received request having expired time budget")
+
+		req, cancel := RequestContextWithTTL(req, 0)
+
+		return req, cancel, false
+
+	case ok && (maxTTL < 1 || budget < maxTTL):
+		req, cancel := RequestContextWithTTL(req, budget)
+
+		return req, cancel, true
+
+	case maxTTL > 0:
+		req, cancel := RequestContextWithTTL(req, maxTTL)
+
+		return req, cancel, true
+	}
+
+	return req, func() {}, true
+}
+
+/*
+OptionHandlerWithTimeoutBudget - see RequestContextWithTimeoutBudget(). Requests having expired budget are not passed
+to 'next', but are replied with 504 Gateway Timeout and Code504General.
+*/
+func OptionHandlerWithTimeoutBudget(header string, maxTTL time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(resp http.ResponseWriter, req *http.Request) {
+				req, cancel, ok := RequestContextWithTimeoutBudget(req, header, maxTTL)
+				defer cancel()
+
+				if !ok {
+					writeResponseError(resp, req, http.StatusGatewayTimeout, ResponseError{Code: Code504General})
+					return
+				}
+
+				next.ServeHTTP(resp, req)
+			},
+		)
+	}
+}
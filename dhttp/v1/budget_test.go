@@ -0,0 +1,85 @@
+package dhttp_test
+
+import (
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestOptionRequestHeaderWithTimeout(t *testing.T) {
+	var ctx, cancel = dctx.WithTTLTimeout(dctx.New(), time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	req = dhttp.OptionRequestHeaderWithTimeout("", 100*time.Millisecond)(req)
+
+	ms, err := strconv.Atoi(req.Header.Get(dhttp.HeaderKeyRequestTimeout))
+	require.NoError(t, err)
+	require.InDelta(t, 900, ms, 50)
+
+	req = dhttp.OptionRequestHeaderWithTimeout("", 2*time.Second)(req)
+	require.Equal(t, "0", req.Header.Get(dhttp.HeaderKeyRequestTimeout))
+
+	// Budget below a millisecond is rounded up.
+	req = dhttp.OptionRequestHeaderWithTimeout("", dctx.Timeout(ctx)-999*time.Microsecond)(req)
+	require.Equal(t, "1", req.Header.Get(dhttp.HeaderKeyRequestTimeout))
+
+	req, err = http.NewRequestWithContext(dctx.New(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	req = dhttp.OptionRequestHeaderWithTimeout("X-Budget", 0)(req)
+	require.Empty(t, req.Header.Get("X-Budget"))
+}
+
+func TestOptionHandlerWithTimeoutBudget(t *testing.T) {
+	var (
+		served  bool
+		timeout time.Duration
+		handler = dhttp.OptionHandlerWithTimeoutBudget("", time.Second)(
+			http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				served = true
+				timeout = dctx.Timeout(req.Context())
+			}),
+		)
+	)
+
+	var serve = func(budget string) int {
+		served = false
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if budget != "" {
+			req.Header.Set(dhttp.HeaderKeyRequestTimeout, budget)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("200"))
+	require.True(t, served)
+	require.InDelta(t, 200*time.Millisecond, timeout, float64(50*time.Millisecond))
+
+	// Capped by server policy, which is applied without budget as well.
+	for _, budget := range []string{"5000", "9223372036854775807", "", "invalid"} {
+		require.Equal(t, http.StatusOK, serve(budget))
+		require.InDelta(t, time.Second, timeout, float64(50*time.Millisecond))
+	}
+
+	require.Equal(t, http.StatusGatewayTimeout, serve("0"))
+	require.False(t, served)
+
+	budget, ok := dhttp.RequestTimeoutBudget(
+		&http.Request{Header: http.Header{dhttp.HeaderKeyRequestTimeout: {"9223372036854775807"}}}, "",
+	)
+	require.True(t, ok)
+	require.Positive(t, budget)
+}
@@ -0,0 +1,419 @@
+package dhttp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dcache/v1"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CachedResponse - is a response cached by OptionHandlerWithCache() or NewCachingClient(). Fields are exported to be
+encoded, e.g. by dcache.Cache.Snapshot().
+*/
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	ETag       string
+	/*
+		Vary - is a list of request headers response depends on. Response having it is cached under a key including
+		these headers values, while a response without status code is cached under a key without them in order to
+		look up the former.
+	*/
+	Vary []string
+	/*
+		Public - is true if response has Cache-Control "public" or "s-maxage", so OptionHandlerWithCache() replies
+		with it to requests having Authorization header as well.
+	*/
+	Public bool
+}
+
+/*
+OptionHandlerWithCache - caches GET and HEAD responses in 'cache', so it is intended to be used with read-heavy
+handlers, see OptionServerWithMiddleware(). 'cache' is a shared one (see RFC 7234), so responses are looked up by
+method, host, path, query and request headers listed in response Vary header. Only 200 OK responses having
+Cache-Control s-maxage or max-age > 0 are cached for s-maxage or max-age respectively, while "private" and "no-store"
+ones are not cached at all, as well as responses varying by "*" or having Set-Cookie header. Responses to requests
+having Authorization header are neither cached nor replied from cache unless they are "public" or have s-maxage.
+Requests having Cache-Control "no-cache" or "no-store" bypass cached responses. Each cached response gets an ETag,
+unless it has one, so requests with matching If-None-Match are replied with 304 Not Modified. Responses are buffered
+entirely before being written, so streaming handlers must not be cached.
+*/
+func OptionHandlerWithCache(cache dcache.Cache[string, CachedResponse]) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(resp http.ResponseWriter, req *http.Request) {
+				if !cachingMethod(req.Method) {
+					next.ServeHTTP(resp, req)
+					return
+				}
+
+				var (
+					key        = cachingKey(req.Method, req.Host+req.URL.Path, req.URL.Query().Encode())
+					reqControl = parseCacheControl(req.Header.Get(HeaderKeyCacheControl))
+					authorized = req.Header.Get(HeaderKeyAuthorization) != ""
+				)
+
+				if !reqControl.noCache && !reqControl.noStore {
+					cached, ok := cachingGet(cache, key, req.Header)
+					if ok && (!authorized || cached.Public) {
+						cachedResponseWrite(resp, req, cached)
+						return
+					}
+				}
+
+				var recorder = newResponseRecorder()
+				next.ServeHTTP(recorder, req)
+
+				cached, ttl, ok := recorder.cachedResponse(authorized)
+				if ok && !reqControl.noStore {
+					cachingSet(cache, key, req.Header, cached, ttl)
+				}
+
+				cachedResponseWrite(resp, req, cached)
+			},
+		)
+	}
+}
+
+/*
+CachingClient - is an IClient caching GET and HEAD responses. Responses are cacheable the same way as with
+OptionHandlerWithCache(), but "private" ones are cached as well, since client cache is a private one. Cached responses
+are returned as if they were sent, so their body must be closed as usual.
+*/
+type CachingClient struct {
+	client IClient
+	cache  dcache.Cache[string, CachedResponse]
+}
+
+func NewCachingClient(client IClient, cache dcache.Cache[string, CachedResponse]) CachingClient {
+	return CachingClient{
+		client: client,
+		cache:  cache,
+	}
+}
+
+func (c CachingClient) Do(req *http.Request) (*http.Response, error) {
+	if !cachingMethod(req.Method) {
+		return c.client.Do(req)
+	}
+
+	var (
+		u          = *req.URL
+		reqControl = parseCacheControl(req.Header.Get(HeaderKeyCacheControl))
+	)
+
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	var key = cachingKey(req.Method, u.String(), req.URL.Query().Encode())
+
+	if !reqControl.noCache && !reqControl.noStore {
+		cached, ok := cachingGet(c.cache, key, req.Header)
+		if ok {
+			return cachedResponseNew(req, cached), nil
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var respControl = parseCacheControl(resp.Header.Get(HeaderKeyCacheControl))
+	if reqControl.noStore || !respControl.cacheable(resp.StatusCode, resp.Header, true, false) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	cachingSet(c.cache, key, req.Header, CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		ETag:       resp.Header.Get(HeaderKeyETag),
+		Vary:       varyHeaders(resp.Header),
+	}, respControl.ttl(true))
+
+	return resp, nil
+}
+
+func cachingMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func cachingKey(method, path, query string) string {
+	return method + " " + path + "?" + query
+}
+
+// cachingVaryKey - appends 'vary' headers values of 'header' to 'key'.
+func cachingVaryKey(key string, vary []string, header http.Header) string {
+	var b strings.Builder
+	b.WriteString(key)
+
+	for _, name := range vary {
+		b.WriteString("\n")
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(strings.Join(header.Values(name), ","))
+	}
+
+	return b.String()
+}
+
+// cachingGet - looks up a response by 'key' and then by 'key' with Vary headers if the response varies.
+func cachingGet(
+	cache dcache.Cache[string, CachedResponse], key string, header http.Header,
+) (
+	CachedResponse, bool,
+) {
+	cached, ok := cache.Get(key)
+	if !ok || cached.StatusCode != 0 {
+		return cached, ok
+	}
+
+	return cache.Get(cachingVaryKey(key, cached.Vary, header))
+}
+
+func cachingSet(
+	cache dcache.Cache[string, CachedResponse], key string, header http.Header, cached CachedResponse, ttl time.Duration,
+) {
+	if len(cached.Vary) == 0 {
+		cache.Set(key, cached, ttl)
+		return
+	}
+
+	cache.Set(key, CachedResponse{Vary: cached.Vary}, ttl)
+	cache.Set(cachingVaryKey(key, cached.Vary, header), cached, ttl)
+}
+
+// cachedResponseWrite - writes 'cached' or 304 Not Modified if 'req' If-None-Match matches its ETag.
+func cachedResponseWrite(resp http.ResponseWriter, req *http.Request, cached CachedResponse) {
+	var header = resp.Header()
+	for key, values := range cached.Header {
+		// Cached values are shared among requests.
+		header[key] = slices.Clone(values)
+	}
+
+	if cached.ETag != "" && cached.StatusCode == http.StatusOK {
+		header.Set(HeaderKeyETag, cached.ETag)
+
+		if etagsMatch(req.Header.Get(HeaderKeyIfNoneMatch), cached.ETag) {
+			header.Del(HeaderKeyContentLength)
+			header.Del(HeaderKeyContentType)
+			resp.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+	}
+
+	resp.WriteHeader(cached.StatusCode)
+
+	if req.Method != http.MethodHead {
+		_, _ = resp.Write(cached.Body)
+	}
+}
+
+func cachedResponseNew(req *http.Request, cached CachedResponse) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(cached.StatusCode) + " " + http.StatusText(cached.StatusCode),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cached.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// etagsMatch - reports if If-None-Match 'header' matches 'etag' using weak comparison.
+func etagsMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+type cacheControl struct {
+	maxAge time.Duration
+	// sMaxAge - overrides maxAge for shared caches.
+	sMaxAge time.Duration
+	noStore bool
+	noCache bool
+	private bool
+	public  bool
+}
+
+func parseCacheControl(header string) cacheControl {
+	var c cacheControl
+
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "max-age":
+			c.maxAge = parseCacheControlSeconds(value)
+
+		case "s-maxage":
+			c.sMaxAge = parseCacheControlSeconds(value)
+
+		case "no-store":
+			c.noStore = true
+
+		case "no-cache":
+			c.noCache = true
+
+		case "private":
+			c.private = true
+
+		case "public":
+			c.public = true
+		}
+	}
+
+	return c
+}
+
+// parseCacheControlSeconds - returns 0 if 'value' is not a positive number of seconds.
+func parseCacheControlSeconds(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil || seconds < 1 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// ttl - returns a time a response is fresh for. 'private' is true if response is cached by a private cache.
+func (c cacheControl) ttl(private bool) time.Duration {
+	if !private && c.sMaxAge > 0 {
+		return c.sMaxAge
+	}
+
+	return c.maxAge
+}
+
+/*
+cacheable - reports if a response may be cached. 'private' is true if response is to be cached by a private cache.
+'authorized' is true if a request has Authorization header, so a shared cache may store response only if it is
+explicitly allowed, see RFC 7234 section 3.2.
+*/
+func (c cacheControl) cacheable(statusCode int, header http.Header, private, authorized bool) bool {
+	return statusCode == http.StatusOK &&
+		c.ttl(private) > 0 &&
+		!c.noStore &&
+		(private || !c.private) &&
+		(private || !authorized || c.shared()) &&
+		header.Get(HeaderKeyVary) != "*" &&
+		len(header.Values(HeaderKeySetCookie)) == 0
+}
+
+// shared - reports if a response may be replied by a shared cache to requests having Authorization header.
+func (c cacheControl) shared() bool {
+	return c.public || c.sMaxAge > 0
+}
+
+// responseRecorder - buffers a response, so it is written by OptionHandlerWithCache() once it is complete.
+type responseRecorder struct {
+	header     http.Header
+	body       bytes.Buffer
+	statusCode int
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+	}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+/*
+cachedResponse - returns recorded response and its ttl. ETag is set if response is cacheable by a shared cache.
+'authorized' is true if a request has Authorization header. Returned bool is false if response is not cacheable.
+*/
+func (r *responseRecorder) cachedResponse(authorized bool) (CachedResponse, time.Duration, bool) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+
+	var cached = CachedResponse{
+		StatusCode: r.statusCode,
+		Header:     r.header,
+		Body:       r.body.Bytes(),
+		ETag:       r.header.Get(HeaderKeyETag),
+	}
+
+	var control = parseCacheControl(r.header.Get(HeaderKeyCacheControl))
+	if !control.cacheable(r.statusCode, r.header, false, authorized) {
+		return cached, 0, false
+	}
+
+	if cached.ETag == "" {
+		sum := sha256.Sum256(cached.Body)
+		cached.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+
+	cached.Vary = varyHeaders(r.header)
+	cached.Public = control.shared()
+
+	return cached, control.ttl(false), true
+}
+
+// varyHeaders - returns canonical request headers names listed in 'header' Vary.
+func varyHeaders(header http.Header) []string {
+	var names []string
+
+	for _, vary := range header.Values(HeaderKeyVary) {
+		for _, name := range strings.Split(vary, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,175 @@
+package dhttp_test
+
+import (
+	"github.com/don-nv/go-dpkg/dcache/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOptionHandlerWithCache(t *testing.T) {
+	var calls atomic.Int32
+
+	handler := dhttp.OptionHandlerWithCache(dcache.NewCache[string, dhttp.CachedResponse]())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+
+			switch r.URL.Path {
+			case "/no-store":
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "no-store")
+
+			case "/vary":
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "max-age=60")
+				w.Header().Set(dhttp.HeaderKeyVary, "Accept-Language")
+
+			default:
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "public, max-age=60")
+			}
+
+			_, _ = w.Write([]byte(r.URL.Path + r.Header.Get("Accept-Language")))
+		}),
+	)
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := serve("/cached?b=2&a=1", nil)
+	require.EqualValues(t, http.StatusOK, w.Code)
+	etag := w.Header().Get(dhttp.HeaderKeyETag)
+	require.NotEmpty(t, etag)
+
+	w = serve("/cached?a=1&b=2", nil)
+	require.EqualValues(t, "/cached", w.Body.String())
+	require.EqualValues(t, 1, calls.Load())
+
+	w = serve("/cached?a=1&b=2", http.Header{dhttp.HeaderKeyIfNoneMatch: {etag}})
+	require.EqualValues(t, http.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.String())
+	require.EqualValues(t, 1, calls.Load())
+
+	serve("/no-store", nil)
+	serve("/no-store", nil)
+	require.EqualValues(t, 3, calls.Load())
+
+	serve("/vary", http.Header{"Accept-Language": {"en"}})
+	w = serve("/vary", http.Header{"Accept-Language": {"de"}})
+	require.EqualValues(t, "/varyde", w.Body.String())
+	w = serve("/vary", http.Header{"Accept-Language": {"en"}})
+	require.EqualValues(t, "/varyen", w.Body.String())
+	require.EqualValues(t, 5, calls.Load())
+}
+
+func TestOptionHandlerWithCache_Shared(t *testing.T) {
+	var calls atomic.Int32
+
+	handler := dhttp.OptionHandlerWithCache(dcache.NewCache[string, dhttp.CachedResponse]())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+
+			switch r.URL.Path {
+			case "/public":
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "public, max-age=60")
+
+			case "/cookie":
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "max-age=60")
+				w.Header().Set(dhttp.HeaderKeySetCookie, "session="+r.Header.Get(dhttp.HeaderKeyAuthorization))
+
+			default:
+				w.Header().Set(dhttp.HeaderKeyCacheControl, "max-age=60")
+			}
+
+			_, _ = w.Write([]byte(r.Host + r.URL.Path + r.Header.Get(dhttp.HeaderKeyAuthorization)))
+		}),
+	)
+
+	serve := func(host, path, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		if authorization != "" {
+			req.Header.Set(dhttp.HeaderKeyAuthorization, authorization)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	t.Run("authorization", func(t *testing.T) {
+		calls.Store(0)
+
+		require.EqualValues(t, "a.test/meBearer alice", serve("a.test", "/me", "Bearer alice").Body.String())
+		require.EqualValues(t, "a.test/meBearer bob", serve("a.test", "/me", "Bearer bob").Body.String())
+		require.EqualValues(t, 2, calls.Load())
+
+		// Response to an anonymous request is cached, but is not replied to an authorized one.
+		require.EqualValues(t, "a.test/me", serve("a.test", "/me", "").Body.String())
+		require.EqualValues(t, "a.test/me", serve("a.test", "/me", "").Body.String())
+		require.EqualValues(t, "a.test/meBearer bob", serve("a.test", "/me", "Bearer bob").Body.String())
+		require.EqualValues(t, 4, calls.Load())
+
+		require.EqualValues(t, "a.test/publicBearer alice", serve("a.test", "/public", "Bearer alice").Body.String())
+		require.EqualValues(t, "a.test/publicBearer alice", serve("a.test", "/public", "Bearer bob").Body.String())
+		require.EqualValues(t, 5, calls.Load())
+	})
+
+	t.Run("set-cookie", func(t *testing.T) {
+		calls.Store(0)
+
+		serve("a.test", "/cookie", "")
+		w := serve("a.test", "/cookie", "")
+		require.EqualValues(t, []string{"session="}, w.Header().Values(dhttp.HeaderKeySetCookie))
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("host", func(t *testing.T) {
+		calls.Store(0)
+
+		require.EqualValues(t, "a.test/host", serve("a.test", "/host", "").Body.String())
+		require.EqualValues(t, "b.test/host", serve("b.test", "/host", "").Body.String())
+		require.EqualValues(t, "a.test/host", serve("a.test", "/host", "").Body.String())
+		require.EqualValues(t, 2, calls.Load())
+	})
+}
+
+func TestCachingClient(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		w.Header().Set(dhttp.HeaderKeyCacheControl, "private, max-age=60")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := dhttp.NewCachingClient(http.DefaultClient, dcache.NewCache[string, dhttp.CachedResponse]())
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/path", nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.EqualValues(t, "ok", body)
+	}
+
+	require.EqualValues(t, 1, calls.Load())
+}
@@ -25,6 +25,7 @@ type Client struct {
 	requestsDefaultTTL     time.Duration
 	log                    dlog.Logger
 	logConfig              LoggerConfig
+	decodeProblems         bool
 }
 
 func MustNewClient(config ClientConfig, log dlog.Logger) Client {
@@ -68,6 +69,7 @@ func NewClient(config ClientConfig, log dlog.Logger) (Client, error) {
 		requestsDefaultOptions: config.RequestsOptions,
 		log:                    log.With().Name("http_client").Build(),
 		logConfig:              config.Logger,
+		decodeProblems:         config.DecodeProblems,
 	}
 
 	return client, nil
@@ -79,8 +81,14 @@ func (c Client) ResetLogging(config LoggerConfig) Client {
 	return c
 }
 
+/*
+Do - sends 'req' as is, but with a new go id and a new child span, see OptionRequestContextWithNewGoID() and
+OptionRequestHeaderWithTrace().
+*/
 func (c Client) Do(req *http.Request) (*http.Response, error) {
-	return c.http.Do(req)
+	return c.http.Do(
+		OptionRequest(req, OptionRequestContextWithNewGoID(), OptionRequestHeaderWithTrace()),
+	)
 }
 
 func (c Client) POST(
@@ -164,7 +172,10 @@ func (c Client) processRequest(
 	return c.sendRequest(req, body, log)
 }
 
-// newRequest - creates new request and OptionRequest().
+/*
+newRequest - creates new request and OptionRequest(). Request gets a new go id and a new child span after all, see
+OptionRequestContextWithNewGoID() and OptionRequestHeaderWithTrace().
+*/
 func (c Client) newRequest(
 	ctx context.Context, method, url string, body []byte, options ...RequestOption,
 ) (
@@ -176,7 +187,9 @@ func (c Client) newRequest(
 	}
 
 	req = OptionRequest(req, c.requestsDefaultOptions...)
-	return OptionRequest(req, options...), nil
+	req = OptionRequest(req, options...)
+
+	return OptionRequest(req, OptionRequestContextWithNewGoID(), OptionRequestHeaderWithTrace()), nil
 }
 
 // sendRequest - sends 'req' and logs request and response according to LoggerConfig{}.
@@ -196,6 +209,13 @@ func (c Client) sendRequest(req *http.Request, body []byte, log dlog.Logger) (*h
 		return nil, fmt.Errorf("creating log with client response: %w", err)
 	}
 
+	if c.decodeProblems {
+		err = ResponseProblem(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return resp, nil
 }
 
@@ -206,6 +226,11 @@ type ClientConfig struct {
 	RequestsDefaultTTL time.Duration
 	Logger             LoggerConfig
 	Proxy              func(*http.Request) (*url.URL, error)
+	/*
+		DecodeProblems - makes Client return *Problem as an error instead of a response having
+		HeaderValueContentTypeProblemJSON content type, see ResponseProblem(). Client.Do() is not affected.
+	*/
+	DecodeProblems bool
 }
 
 func (c ClientConfig) validate() error {
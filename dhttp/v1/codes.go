@@ -11,10 +11,14 @@ type CodeError int
 
 const (
 	Code400General         CodeError = 40000
+	Code401General         CodeError = 40100
 	Code403General         CodeError = 40300
 	Code403AccessForbidden CodeError = 40301
 	Code404General         CodeError = 40400
+	Code409General         CodeError = 40900
+	Code499General         CodeError = 49900
 	Code500General         CodeError = 50000
+	Code504General         CodeError = 50400
 )
 
 // IsHTTP - indicates if CodeError belongs to HTTP status code group, where 'code' is valid http status code.
@@ -0,0 +1,150 @@
+package dhttp
+
+import (
+	"context"
+	"errors"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/djson/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"net/http"
+	"sync"
+)
+
+// StatusClientClosedRequest - is a non-standard status code of requests canceled by a client.
+const StatusClientClosedRequest = 499
+
+/*
+ErrorMapper - returns HTTP status code and CodeError respective to 'err'. Returned bool is false if 'err' is not
+mapped. See RegisterErrorMapper().
+*/
+type ErrorMapper func(err error) (int, CodeError, bool)
+
+var errorMappers struct {
+	mu   sync.RWMutex
+	list []ErrorMapper
+}
+
+/*
+RegisterErrorMapper - registers 'm' used by ErrorStatus(). Mappers are checked in reverse registration order, so
+mappers registered later take precedence. Default mappers are registered on package initialization, see ErrorStatus().
+*/
+func RegisterErrorMapper(m ErrorMapper) {
+	errorMappers.mu.Lock()
+	defer errorMappers.mu.Unlock()
+
+	errorMappers.list = append(errorMappers.list, m)
+}
+
+// RegisterError - maps errors having 'target' in their tree (see errors.Is()) to 'statusCode' and 'code'.
+func RegisterError(target error, statusCode int, code CodeError) {
+	RegisterErrorMapper(func(err error) (int, CodeError, bool) {
+		return statusCode, code, errors.Is(err, target)
+	})
+}
+
+// RegisterErrorAs - maps errors having T in their tree (see errors.As()) to 'statusCode' and 'code'.
+func RegisterErrorAs[T error](statusCode int, code CodeError) {
+	RegisterErrorMapper(func(err error) (int, CodeError, bool) {
+		var target T
+
+		return statusCode, code, errors.As(err, &target)
+	})
+}
+
+/*
+ErrorStatus - returns HTTP status code and CodeError respective to 'err' by registered mappers, see
+RegisterErrorMapper(). Default mappings in descending precedence:
+  - derr.Error having HTTP status code - its status code and respective general CodeError, e.g. Code404General;
+  - derr.ErrUnauthorized - 403 Forbidden and Code403AccessForbidden;
+  - derr.ErrUnauthenticated - 401 Unauthorized and Code401General;
+  - derr.ErrDuplicated - 409 Conflict and Code409General;
+  - derr.ErrNotFound - 404 Not Found and Code404General;
+  - context.DeadlineExceeded - 504 Gateway Timeout and Code504General;
+  - context.Canceled - 499 StatusClientClosedRequest and Code499General;
+  - other - 500 Internal Server Error and Code500General;
+*/
+func ErrorStatus(err error) (int, CodeError) {
+	errorMappers.mu.RLock()
+	defer errorMappers.mu.RUnlock()
+
+	for i := len(errorMappers.list) - 1; i >= 0; i-- {
+		statusCode, code, ok := errorMappers.list[i](err)
+		if ok {
+			return statusCode, code
+		}
+	}
+
+	return http.StatusInternalServerError, Code500General
+}
+
+/*
+NewResponseError - returns HTTP status code and ResponseError respective to 'err', see ErrorStatus(). Message is a
+public message of derr.Error found in 'err' tree, while other error details are not exposed. 5xx causes are logged.
+*/
+func NewResponseError(ctx context.Context, err error) (int, ResponseError) {
+	statusCode, code := ErrorStatus(err)
+	if statusCode >= http.StatusInternalServerError {
+		dlog.E().Scope(ctx).Any("error", err).Any("status", statusCode).Write("replying with server error")
+	}
+
+	var resp = ResponseError{Code: code}
+
+	var derror *derr.Error
+	if errors.As(err, &derror) {
+		resp.Message = derror.Message()
+	}
+
+	return statusCode, resp
+}
+
+/*
+WriteError - writes ResponseError respective to 'err' (see NewResponseError()) or respective Problem if it is accepted
+by 'req', see RequestProblemsAccepted().
+*/
+func WriteError(resp http.ResponseWriter, req *http.Request, err error) {
+	statusCode, respErr := NewResponseError(req.Context(), err)
+
+	writeResponseError(resp, req, statusCode, respErr)
+}
+
+/*
+writeResponseError - writes 'respErr' or respective Problem if it is accepted by 'req', see RequestProblemsAccepted().
+*/
+func writeResponseError(resp http.ResponseWriter, req *http.Request, statusCode int, respErr ResponseError) {
+	var (
+		contentType     = HeaderValueContentTypeJSON
+		v           any = respErr
+	)
+
+	if RequestProblemsAccepted(req) {
+		contentType = HeaderValueContentTypeProblemJSON
+		v = NewProblem(req, statusCode, respErr)
+	}
+
+	body, err := djson.Marshal(v)
+	if err != nil {
+		resp.WriteHeader(statusCode)
+		return
+	}
+
+	resp.Header().Set(HeaderKeyContentType, contentType)
+	resp.WriteHeader(statusCode)
+	_, _ = resp.Write(body)
+}
+
+func init() {
+	RegisterError(context.Canceled, StatusClientClosedRequest, Code499General)
+	RegisterError(context.DeadlineExceeded, http.StatusGatewayTimeout, Code504General)
+	RegisterError(derr.ErrNotFound, http.StatusNotFound, Code404General)
+	RegisterError(derr.ErrDuplicated, http.StatusConflict, Code409General)
+	RegisterError(derr.ErrUnauthenticated, http.StatusUnauthorized, Code401General)
+	RegisterError(derr.ErrUnauthorized, http.StatusForbidden, Code403AccessForbidden)
+	RegisterErrorMapper(func(err error) (int, CodeError, bool) {
+		var derror *derr.Error
+		if !errors.As(err, &derror) || derror.HTTPStatus() == 0 {
+			return 0, 0, false
+		}
+
+		return derror.HTTPStatus(), CodeError(derror.HTTPStatus() * 100), true
+	})
+}
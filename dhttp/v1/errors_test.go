@@ -0,0 +1,72 @@
+package dhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type errValidation struct {
+	field string
+}
+
+func (e errValidation) Error() string {
+	return fmt.Sprintf("invalid %q", e.field)
+}
+
+func TestErrorStatus(t *testing.T) {
+	dhttp.RegisterErrorAs[errValidation](http.StatusBadRequest, dhttp.Code400General)
+
+	for err, expected := range map[error]struct {
+		statusCode int
+		code       dhttp.CodeError
+	}{
+		fmt.Errorf("getting: %w", derr.ErrNotFound):                {http.StatusNotFound, dhttp.Code404General},
+		fmt.Errorf("inserting: %w", derr.ErrDuplicated):            {http.StatusConflict, dhttp.Code409General},
+		errors.Join(derr.ErrUnauthenticated, derr.ErrUnauthorized): {http.StatusForbidden, dhttp.Code403AccessForbidden},
+		fmt.Errorf("sending: %w", context.DeadlineExceeded):        {http.StatusGatewayTimeout, dhttp.Code504General},
+		context.Canceled: {dhttp.StatusClientClosedRequest, dhttp.Code499General},
+		fmt.Errorf("parsing: %w", errValidation{field: "id"}):           {http.StatusBadRequest, dhttp.Code400General},
+		derr.New("gone").Status(http.StatusGone).Wrap(derr.ErrNotFound): {http.StatusGone, 41000},
+		errors.New("internal"): {http.StatusInternalServerError, dhttp.Code500General},
+	} {
+		statusCode, code := dhttp.ErrorStatus(err)
+		require.Equal(t, expected.statusCode, statusCode, err.Error())
+		require.Equal(t, expected.code, code, err.Error())
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	for err, expected := range map[error]struct {
+		statusCode int
+		resp       dhttp.ResponseError
+	}{
+		derr.New("order_not_found").Msg("order is not found").Wrap(derr.ErrNotFound): {
+			statusCode: http.StatusNotFound,
+			resp:       dhttp.ResponseError{Code: dhttp.Code404General, Message: "order is not found"},
+		},
+		fmt.Errorf("querying: %w", errors.New("connection refused")): {
+			statusCode: http.StatusInternalServerError,
+			resp:       dhttp.ResponseError{Code: dhttp.Code500General},
+		},
+	} {
+		var (
+			req  = httptest.NewRequest(http.MethodGet, "/", nil)
+			resp = httptest.NewRecorder()
+		)
+
+		dhttp.WriteError(resp, req, err)
+		require.Equal(t, expected.statusCode, resp.Code)
+
+		var respErr dhttp.ResponseError
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &respErr))
+		require.Equal(t, expected.resp, respErr)
+	}
+}
@@ -12,6 +12,8 @@ import (
 OptionHandlerWithDefaults
   - OptionHandlerWithRecover;
   - OptionHandlerWithXRequestID;
+  - OptionHandlerWithKeys;
+  - OptionHandlerWithTrace;
   - OptionHandlerWithGoID;
 */
 func OptionHandlerWithDefaults(next http.Handler) http.Handler {
@@ -19,6 +21,8 @@ func OptionHandlerWithDefaults(next http.Handler) http.Handler {
 		func(resp http.ResponseWriter, req *http.Request) {
 			next = OptionHandlerWithRecover(next)
 			next = OptionHandlerWithXRequestID(next)
+			next = OptionHandlerWithKeys(next)
+			next = OptionHandlerWithTrace(next)
 			next = OptionHandlerWithGoID(next)
 
 			next.ServeHTTP(resp, req)
@@ -37,6 +41,28 @@ func OptionHandlerWithGoID(next http.Handler) http.Handler {
 	)
 }
 
+// OptionHandlerWithKeys - see OptionRequestContextWithKeys().
+func OptionHandlerWithKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(resp http.ResponseWriter, req *http.Request) {
+			req = OptionRequestContextWithKeys()(req)
+
+			next.ServeHTTP(resp, req)
+		},
+	)
+}
+
+// OptionHandlerWithTrace - see OptionRequestContextWithTrace().
+func OptionHandlerWithTrace(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(resp http.ResponseWriter, req *http.Request) {
+			req = OptionRequestContextWithTrace()(req)
+
+			next.ServeHTTP(resp, req)
+		},
+	)
+}
+
 // OptionHandlerWithTTL - see OptionRequestContextWithTTL().
 func OptionHandlerWithTTL(d time.Duration) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -17,6 +17,14 @@ const (
 	HeaderKeyXRequestID               = "X-Request-Id"
 	HeaderKeyContentLength            = "Content-Length"
 	HeaderKeyAuthorization            = "Authorization"
+	HeaderKeyTraceparent              = "Traceparent"
+	HeaderKeyTracestate               = "Tracestate"
+	HeaderKeyAccept                   = "Accept"
+	HeaderKeyCacheControl             = "Cache-Control"
+	HeaderKeyETag                     = "ETag"
+	HeaderKeyIfNoneMatch              = "If-None-Match"
+	HeaderKeyVary                     = "Vary"
+	HeaderKeySetCookie                = "Set-Cookie"
 )
 
 /*
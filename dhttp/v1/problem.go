@@ -0,0 +1,189 @@
+package dhttp
+
+import (
+	"context"
+	"fmt"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/djson/v1"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderValueContentTypeProblemJSON - is an RFC 7807 problem details media type.
+	HeaderValueContentTypeProblemJSON = "application/problem+json"
+	// ProblemTypeDefault - is a Problem type meaning that a problem has no semantics beyond its HTTP status code.
+	ProblemTypeDefault = "about:blank"
+)
+
+/*
+Problem - is an RFC 7807 problem details, see https://www.rfc-editor.org/rfc/rfc7807. It is an alternative to
+ResponseError replied if a client accepts HeaderValueContentTypeProblemJSON or a handler is wrapped by
+OptionHandlerWithProblems(). Problem is an error, so it is returned by Client receiving it, see
+ClientConfig.DecodeProblems and ResponseProblem().
+*/
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code - is an extension member, see ResponseError.
+	Code CodeError `json:"code,omitempty"`
+	// XRequestID - is an extension member, see dctx.XRequestID().
+	XRequestID string `json:"x_req_id,omitempty"`
+	// Extensions - are other extension members. Members having names of the fields above are ignored.
+	Extensions map[string]any `json:"-"`
+}
+
+// problemMembers - is Problem without methods, so it is (un)marshalled by default.
+type problemMembers Problem
+
+/*
+NewProblem - returns Problem respective to 'respErr' replied to 'req' with 'statusCode'. Title is the status code
+text, while detail is ResponseError message.
+*/
+func NewProblem(req *http.Request, statusCode int, respErr ResponseError) *Problem {
+	return &Problem{
+		Type:       ProblemTypeDefault,
+		Title:      http.StatusText(statusCode),
+		Status:     statusCode,
+		Detail:     respErr.Message,
+		Instance:   req.URL.Path,
+		Code:       respErr.Code,
+		XRequestID: dctx.XRequestID(req.Context()),
+	}
+}
+
+func (p *Problem) Error() string {
+	var msg = fmt.Sprintf("problem %d %s, code %d", p.Status, p.Title, p.Code)
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+
+	return msg
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members, err := djson.Marshal(problemMembers(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return members, err
+	}
+
+	var object = make(map[string]any, len(p.Extensions))
+	for k, v := range p.Extensions {
+		object[k] = v
+	}
+
+	// Problem members take precedence over extensions having the same names.
+	err = djson.Unmarshal(members, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	return djson.Marshal(object)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	err := djson.Unmarshal(data, (*problemMembers)(p))
+	if err != nil {
+		return err
+	}
+
+	var object map[string]any
+
+	err = djson.Unmarshal(data, &object)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range []string{"type", "title", "status", "detail", "instance", "code", "x_req_id"} {
+		delete(object, member)
+	}
+
+	p.Extensions = nil
+	if len(object) > 0 {
+		p.Extensions = object
+	}
+
+	return nil
+}
+
+/*
+ResponseProblem - decodes 'resp' body into Problem if 'resp' content type is HeaderValueContentTypeProblemJSON. In that
+case body is closed and Problem is returned as an error, otherwise nil is returned and 'resp' is left intact.
+*/
+func ResponseProblem(resp *http.Response) error {
+	if !isContentTypeProblem(resp.Header.Get(HeaderKeyContentType)) {
+		return nil
+	}
+
+	buff, err := NewClientResponseBuffer(resp)
+	if err != nil {
+		return fmt.Errorf("reading problem: %w", err)
+	}
+	defer buff.Release()
+
+	var problem Problem
+
+	err = djson.Unmarshal(buff.Body(), &problem)
+	if err != nil {
+		return fmt.Errorf("unmarshalling problem: %w", err)
+	}
+
+	return &problem
+}
+
+type problemsContextKey struct{}
+
+/*
+OptionHandlerWithProblems - makes error writers of this package (e.g. WriteError()) reply with Problem instead of
+ResponseError regardless of request "Accept" header. See OptionServerWithProblems().
+*/
+func OptionHandlerWithProblems(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(resp http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(resp, RequestWithProblems(req))
+		},
+	)
+}
+
+// OptionServerWithProblems - is the same as OptionServerWithMiddleware() with OptionHandlerWithProblems().
+func OptionServerWithProblems() ServerOption {
+	return OptionServerWithMiddleware(OptionHandlerWithProblems)
+}
+
+// RequestWithProblems - is the same as OptionHandlerWithProblems(), but for 'req' only. Returns a shallow copy.
+func RequestWithProblems(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), problemsContextKey{}, true))
+}
+
+/*
+RequestProblemsAccepted - reports if 'req' is to be replied with Problem instead of ResponseError: either 'req' is
+wrapped by RequestWithProblems() or its "Accept" header has HeaderValueContentTypeProblemJSON.
+*/
+func RequestProblemsAccepted(req *http.Request) bool {
+	if ok, _ := req.Context().Value(problemsContextKey{}).(bool); ok {
+		return true
+	}
+
+	for _, accept := range req.Header.Values(HeaderKeyAccept) {
+		for _, mediaType := range strings.Split(accept, ",") {
+			if isContentTypeProblem(mediaType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isContentTypeProblem(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == HeaderValueContentTypeProblemJSON
+}
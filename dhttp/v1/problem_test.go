@@ -0,0 +1,118 @@
+package dhttp_test
+
+import (
+	"encoding/json"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError_Problem(t *testing.T) {
+	var (
+		err     = derr.New("order_not_found").Msg("order is not found").Wrap(derr.ErrNotFound)
+		handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			dhttp.WriteError(resp, req, err)
+		})
+		expected = dhttp.Problem{
+			Type:       dhttp.ProblemTypeDefault,
+			Title:      "Not Found",
+			Status:     http.StatusNotFound,
+			Detail:     "order is not found",
+			Instance:   "/orders/1",
+			Code:       dhttp.Code404General,
+			XRequestID: "id",
+		}
+	)
+
+	for name, test := range map[string]struct {
+		handler http.Handler
+		accept  string
+		problem bool
+	}{
+		"accepted":     {handler: handler, accept: "application/json;q=0.5, application/problem+json", problem: true},
+		"not accepted": {handler: handler, accept: "application/json"},
+		"server option": {
+			handler: dhttp.OptionHandlerWithProblems(handler),
+			accept:  "application/json",
+			problem: true,
+		},
+	} {
+		var (
+			req  = httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			resp = httptest.NewRecorder()
+		)
+
+		req = req.WithContext(dctx.WithXRequestID(req.Context(), "id"))
+		req.Header.Set(dhttp.HeaderKeyAccept, test.accept)
+
+		test.handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code, name)
+
+		if !test.problem {
+			require.Equal(t, dhttp.HeaderValueContentTypeJSON, resp.Header().Get(dhttp.HeaderKeyContentType), name)
+			continue
+		}
+
+		require.Equal(t, dhttp.HeaderValueContentTypeProblemJSON, resp.Header().Get(dhttp.HeaderKeyContentType), name)
+
+		var problem dhttp.Problem
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &problem), name)
+		require.Equal(t, expected, problem, name)
+	}
+}
+
+func TestProblem_JSON(t *testing.T) {
+	var problem = dhttp.Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "You do not have enough credit.",
+		Status:     http.StatusForbidden,
+		Code:       dhttp.Code403General,
+		Extensions: map[string]any{"balance": float64(30), "title": "ignored"},
+	}
+
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+	require.JSONEq(
+		t,
+		`{
+			"type":"https://example.com/probs/out-of-credit",
+			"title":"You do not have enough credit.",
+			"status":403,
+			"code":40300,
+			"balance":30
+		}`,
+		string(data),
+	)
+
+	var decoded dhttp.Problem
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	problem.Extensions = map[string]any{"balance": float64(30)}
+	require.Equal(t, problem, decoded)
+}
+
+func TestClient_DecodeProblems(t *testing.T) {
+	var srv = httptest.NewServer(dhttp.OptionHandlerWithXRequestID(dhttp.OptionHandlerWithProblems(
+		http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			dhttp.WriteError(resp, req, derr.ErrDuplicated)
+		}),
+	)))
+	defer srv.Close()
+
+	client := dhttp.MustNewClient(dhttp.ClientConfig{DecodeProblems: true}, dlog.New())
+
+	resp, err := client.GET(dctx.New(), srv.URL+"/orders")
+	require.Nil(t, resp)
+
+	var problem *dhttp.Problem
+	require.ErrorAs(t, err, &problem)
+	require.Equal(t, http.StatusConflict, problem.Status)
+	require.Equal(t, dhttp.Code409General, problem.Code)
+	require.Equal(t, "/orders", problem.Instance)
+	require.NotEmpty(t, problem.XRequestID)
+}
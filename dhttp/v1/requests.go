@@ -8,6 +8,7 @@ import (
 	"github.com/don-nv/go-dpkg/dlog/v1"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -80,8 +81,8 @@ func OptionRequestHeaderWithXRequestID() RequestOption {
 }
 
 /*
-OptionRequestContextWithNewGoID - is used to populate request context with dctx.WithNewGoID(). Returned request is a
-shallow copy of 'req'.
+OptionRequestContextWithNewGoID - is used to populate request context with dctx.WithNewGoID(), so request context go
+id has a caller one as a parent. Returned request is a shallow copy of 'req'.
 */
 func OptionRequestContextWithNewGoID() RequestOption {
 	return func(req *http.Request) *http.Request {
@@ -113,6 +114,111 @@ func OptionRequestContextWithXRequestID() RequestOption {
 	}
 }
 
+/*
+OptionRequestHeaderWithTrace - is used to populate request W3C Trace Context headers with a new child span of
+dctx.TraceOf() or with a root span of a new trace if there is none. Returned request is a shallow copy of 'req' having
+the span in its context.
+*/
+func OptionRequestHeaderWithTrace() RequestOption {
+	return func(req *http.Request) *http.Request {
+		var ctx = req.Context()
+
+		trace, ok := dctx.TraceOf(ctx)
+		if ok {
+			trace = trace.Child()
+		} else {
+			trace = dctx.NewTrace()
+		}
+
+		req.Header.Set(HeaderKeyTraceparent, trace.Traceparent())
+		if trace.State != "" {
+			req.Header.Set(HeaderKeyTracestate, trace.State)
+		} else {
+			req.Header.Del(HeaderKeyTracestate)
+		}
+
+		return req.WithContext(dctx.WithTrace(ctx, trace))
+	}
+}
+
+/*
+OptionRequestContextWithTrace - is used to populate request context with a span called by the one found in W3C Trace
+Context headers, see dctx.ParseTraceparent(). If headers are missing or invalid, a new trace is started. Returned
+request is a shallow copy of 'req'.
+*/
+func OptionRequestContextWithTrace() RequestOption {
+	return func(req *http.Request) *http.Request {
+		trace, err := dctx.ParseTraceparent(
+			req.Header.Get(HeaderKeyTraceparent),
+			strings.Join(req.Header.Values(HeaderKeyTracestate), ","),
+		)
+		if err != nil {
+			trace = dctx.NewTrace()
+		}
+
+		return req.WithContext(dctx.WithTrace(req.Context(), trace))
+	}
+}
+
+/*
+OptionRequestHeaderWithKeys - is used to populate request headers with values of each registered dctx key having a
+header, see dctx.NewKey(). Missing values get omitted.
+*/
+func OptionRequestHeaderWithKeys() RequestOption {
+	return func(req *http.Request) *http.Request {
+		var ctx = req.Context()
+
+		dctx.RangeKeys(func(k dctx.AnyKey) bool {
+			if k.Header() == "" {
+				return true
+			}
+
+			v, ok := k.Format(ctx)
+			if ok {
+				req.Header.Set(k.Header(), v)
+			}
+
+			return true
+		})
+
+		return req
+	}
+}
+
+/*
+OptionRequestContextWithKeys - is used to populate request context with each registered dctx key value found in
+respective header, see dctx.NewKey(). Values failed to be parsed are logged and omitted. Returned request is a shallow
+copy of 'req'.
+*/
+func OptionRequestContextWithKeys() RequestOption {
+	return func(req *http.Request) *http.Request {
+		var ctx = req.Context()
+
+		dctx.RangeKeys(func(k dctx.AnyKey) bool {
+			if k.Header() == "" {
+				return true
+			}
+
+			v := req.Header.Get(k.Header())
+			if v == "" {
+				return true
+			}
+
+			parsed, err := k.WithParsed(ctx, v)
+			if err != nil {
+				dlog.E().Scope(ctx).Any("header", k.Header()).Writef("%s", err)
+				return true
+			}
+
+			ctx = parsed
+
+			return true
+		})
+
+		return req.WithContext(ctx)
+	}
+}
+
 // RequestBodyAppendAndKeep - reads 'req' body into 'buffer' keeping 'req' body io.ReadCloser unread.
 func RequestBodyAppendAndKeep(req *http.Request, body []byte) ([]byte, error) {
 	var buff = bytes.NewBuffer(body)
@@ -50,3 +50,27 @@ func TestRequestContextWithMaxTTL(t *testing.T) {
 		})
 	}
 }
+
+var keyTestTenant = dctx.NewKey[string](
+	"test_tenant", dctx.KeyOptionWithHeader("X-Tenant-Id"), dctx.KeyOptionWithLogField("tenant_id"),
+)
+
+func TestOptionRequestKeys(t *testing.T) {
+	var ctx = dctx.New(keyTestTenant.Option("acme"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	req = dhttp.OptionRequestHeaderWithKeys()(req)
+	require.Equal(t, "acme", req.Header.Get("X-Tenant-Id"))
+
+	inbound, err := http.NewRequestWithContext(dctx.New(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	inbound.Header = req.Header.Clone()
+	inbound = dhttp.OptionRequestContextWithKeys()(inbound)
+
+	tenant, ok := keyTestTenant.Get(inbound.Context())
+	require.True(t, ok)
+	require.Equal(t, "acme", tenant)
+}
@@ -3,7 +3,10 @@ package dgin
 import (
 	"github.com/don-nv/go-dpkg/dctx/v1"
 	dhttp "github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
 )
 
 /*
@@ -12,6 +15,8 @@ OptionHandlerWithDefaults
 Default preset:
   - dhttp.OptionRequestContextWithNewGoID;
   - dhttp.OptionRequestContextWithXRequestID;
+  - dhttp.OptionRequestContextWithKeys;
+  - dhttp.OptionRequestContextWithTrace;
   - dhttp.OptionResponseWriterHeaderWithXRequestID;
 */
 func OptionHandlerWithDefaults(c *gin.Context) {
@@ -19,6 +24,8 @@ func OptionHandlerWithDefaults(c *gin.Context) {
 
 	req = dhttp.OptionRequestContextWithNewGoID()(req)
 	req = dhttp.OptionRequestContextWithXRequestID()(req)
+	req = dhttp.OptionRequestContextWithKeys()(req)
+	req = dhttp.OptionRequestContextWithTrace()(req)
 
 	var id = dctx.XRequestID(req.Context())
 	dhttp.OptionResponseWriterHeaderWithXRequestID(c.Writer, id)
@@ -27,3 +34,68 @@ func OptionHandlerWithDefaults(c *gin.Context) {
 
 	c.Next()
 }
+
+/*
+OptionHandlerWithTimeoutBudget - see dhttp.RequestContextWithTimeoutBudget(). Requests having expired budget are
+aborted with 504 Gateway Timeout and dhttp.Code504General.
+*/
+func OptionHandlerWithTimeoutBudget(header string, maxTTL time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, cancel, ok := dhttp.RequestContextWithTimeoutBudget(c.Request, header, maxTTL)
+		defer cancel()
+
+		if !ok {
+			Abort(c, http.StatusGatewayTimeout, dhttp.Code504General, nil)
+			return
+		}
+
+		c.Request = req
+
+		c.Next()
+	}
+}
+
+// OptionHandlerWithAuthenticator - see dhttp.OptionHandlerWithAuthenticator().
+func OptionHandlerWithAuthenticator(a dhttp.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.Authenticate(c.Request)
+		if err != nil {
+			abortAuth(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(dctx.WithPrincipal(c.Request.Context(), principal))
+
+		c.Next()
+	}
+}
+
+// OptionHandlerWithScopes - see dhttp.OptionHandlerWithScopes().
+func OptionHandlerWithScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := dctx.RequireScope(c.Request.Context(), scopes...)
+		if err != nil {
+			abortAuth(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func abortAuth(c *gin.Context, err error) {
+	statusCode, code := dhttp.AuthErrorStatus(err)
+	if statusCode == http.StatusInternalServerError {
+		dlog.E().Scope(c.Request.Context()).Writef("authenticating: %s", err)
+	}
+
+	// Error text is not exposed, the same as by dhttp.OptionHandlerWithAuthenticator().
+	Abort(c, statusCode, code, nil)
+}
+
+// OptionHandlerWithProblems - see dhttp.OptionHandlerWithProblems().
+func OptionHandlerWithProblems(c *gin.Context) {
+	c.Request = dhttp.RequestWithProblems(c.Request)
+
+	c.Next()
+}
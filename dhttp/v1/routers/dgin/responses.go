@@ -38,6 +38,10 @@ func R500(c *gin.Context) {
 	Abort(c, http.StatusInternalServerError, dhttp.Code500General, nil)
 }
 
+/*
+Abort - aborts with dhttp.ResponseError or respective dhttp.Problem if it is accepted by a request, see
+dhttp.RequestProblemsAccepted().
+*/
 func Abort(c *gin.Context, httpCode int, codeError dhttp.CodeError, err error) {
 	var msg string
 
@@ -50,5 +54,26 @@ func Abort(c *gin.Context, httpCode int, codeError dhttp.CodeError, err error) {
 		Message: msg,
 	}
 
-	c.AbortWithStatusJSON(httpCode, resp)
+	abortResponseError(c, httpCode, resp)
+}
+
+/*
+AbortError - aborts with dhttp.ResponseError respective to 'err' (see dhttp.NewResponseError()) or respective
+dhttp.Problem, see Abort().
+*/
+func AbortError(c *gin.Context, err error) {
+	statusCode, resp := dhttp.NewResponseError(c.Request.Context(), err)
+
+	abortResponseError(c, statusCode, resp)
+}
+
+func abortResponseError(c *gin.Context, statusCode int, resp dhttp.ResponseError) {
+	if !dhttp.RequestProblemsAccepted(c.Request) {
+		c.AbortWithStatusJSON(statusCode, resp)
+		return
+	}
+
+	// Content type is set in advance, so it is not replaced by a JSON one.
+	c.Header(dhttp.HeaderKeyContentType, dhttp.HeaderValueContentTypeProblemJSON)
+	c.AbortWithStatusJSON(statusCode, dhttp.NewProblem(c.Request, statusCode, resp))
 }
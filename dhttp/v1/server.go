@@ -7,6 +7,7 @@ import (
 	"github.com/don-nv/go-dpkg/derr/v1"
 	"github.com/don-nv/go-dpkg/dlog/v1"
 	"github.com/don-nv/go-dpkg/dsync/v1"
+	"net"
 	"net/http"
 	"time"
 )
@@ -97,6 +98,18 @@ func OptionServerWithTLS(config *tls.Config) ServerOption {
 	}
 }
 
+/*
+OptionServerWithBaseContext - sets 'ctx' as a base of requests contexts, so in-flight requests are canceled once 'ctx'
+is done, e.g. by shutdown.Coordinator, while Server.Running() context stops accepting new ones.
+*/
+func OptionServerWithBaseContext(ctx context.Context) ServerOption {
+	return func(server *http.Server) {
+		server.BaseContext = func(net.Listener) context.Context {
+			return ctx
+		}
+	}
+}
+
 type ServerConfig struct {
 	Address              string
 	RequestReadHeaderTTL time.Duration
@@ -0,0 +1,63 @@
+package dhttp_test
+
+import (
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/dhttp/v1"
+	"github.com/don-nv/go-dpkg/dlog/v1"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracePropagation(t *testing.T) {
+	var (
+		received = make(chan dctx.Trace, 2)
+		header   = make(chan http.Header, 2)
+	)
+
+	srv := httptest.NewServer(dhttp.OptionHandlerWithDefaults(
+		http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			trace, _ := dctx.TraceOf(req.Context())
+
+			received <- trace
+			header <- req.Header.Clone()
+		}),
+	))
+	defer srv.Close()
+
+	client := dhttp.MustNewClient(dhttp.ClientConfig{}, dlog.New())
+
+	var trace = dctx.NewTrace()
+	trace.State = "vendor=value"
+
+	var ctx = dctx.New(dctx.OptionWithTrace(trace))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.GET(ctx, srv.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	var first, second = <-received, <-received
+	require.Equal(t, trace.TraceID, first.TraceID)
+	require.Equal(t, trace.TraceID, second.TraceID)
+	// Each outgoing call is a new child span.
+	require.NotEqual(t, first.ParentSpanID, second.ParentSpanID)
+	require.NotEqual(t, trace.SpanID, first.ParentSpanID)
+	require.Equal(t, "vendor=value", first.State)
+	require.Equal(t, "vendor=value", (<-header).Get(dhttp.HeaderKeyTracestate))
+
+	// Invalid traceparent starts a new trace.
+	req, err := http.NewRequestWithContext(dctx.New(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(dhttp.HeaderKeyTraceparent, "invalid")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	var started = <-received
+	require.Len(t, started.TraceID, 32)
+	require.Empty(t, started.ParentSpanID)
+}
@@ -169,7 +169,15 @@ func (d Data) Strings(key string, value []string) Data {
 	return d
 }
 
+// Error - logs derr.Error found in 'value' tree as an object having its code, details and stack, see derr.Error.
 func (d Data) Error(key string, value error) Data {
+	object, ok := newErrorObject(value)
+	if ok {
+		d.zctx = d.zctx.Object(key, object)
+
+		return d
+	}
+
 	d.zctx = d.zctx.AnErr(key, value)
 
 	return d
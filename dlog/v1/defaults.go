@@ -32,22 +32,30 @@ const (
 type ReadScopeFn func(ctx context.Context, data Data) Data
 
 /*
-ReadScopeDefault - default ReadScopeFn function. Uses dctx package to populate Logger with:
-  - Goroutine id;
+ReadScopeDefault - default ReadScopeFn function. Uses dctx package to populate Logger with values of each registered
+key having a log field, see dctx.NewKey(). They include:
+  - Goroutine id and its parent one;
   - X request id;
 
-Both values are expected to be set to 'ctx' via dctx package. If some of them are missing, then the respective empty
-values get omitted.
+Missing values get omitted. Trace id and span id of dctx.TraceOf() and context deadline are added as well if any.
 */
 func ReadScopeDefault(ctx context.Context, data Data) Data {
-	id := dctx.GoID(ctx)
-	if id != "" {
-		data = data.String("go_id", id)
-	}
+	dctx.RangeKeys(func(k dctx.AnyKey) bool {
+		if k.LogField() == "" {
+			return true
+		}
+
+		v, ok := k.Format(ctx)
+		if ok && v != "" {
+			data = data.String(k.LogField(), v)
+		}
 
-	id = dctx.XRequestID(ctx)
-	if id != "" {
-		data = data.String("x_req_id", id)
+		return true
+	})
+
+	trace, ok := dctx.TraceOf(ctx)
+	if ok {
+		data = data.String("trace_id", trace.TraceID).String("span_id", trace.SpanID)
 	}
 
 	deadline, ok := ctx.Deadline()
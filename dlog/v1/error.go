@@ -0,0 +1,65 @@
+package dlog
+
+import (
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/rs/zerolog"
+)
+
+// errorObject - logs derr.Error as an object having its code, details and stack.
+type errorObject struct {
+	err    error
+	derror *derr.Error
+}
+
+func (o errorObject) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("message", o.err.Error())
+	e.Str("code", o.derror.Code())
+
+	if status := o.derror.HTTPStatus(); status != 0 {
+		e.Int("status", status)
+	}
+
+	if msg := o.derror.Message(); msg != "" {
+		e.Str("public", msg)
+	}
+
+	var fields = o.derror.Fields()
+	if len(fields) > 0 {
+		var dict = zerolog.Dict()
+
+		for _, field := range fields {
+			switch v := field.Value.(type) {
+			case error:
+				dict.AnErr(field.Key, v)
+
+			default:
+				dict.Interface(field.Key, v)
+			}
+		}
+
+		e.Dict("fields", dict)
+	}
+
+	var (
+		frames = o.derror.Frames()
+		stack  = make([]string, 0, len(frames))
+	)
+
+	for _, frame := range frames {
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+	}
+
+	e.Strs("stack", stack)
+}
+
+// newErrorObject - returns errorObject if 'err' tree has derr.Error. Returned bool is false otherwise.
+func newErrorObject(err error) (errorObject, bool) {
+	var derror *derr.Error
+	if !errors.As(err, &derror) {
+		return errorObject{}, false
+	}
+
+	return errorObject{err: err, derror: derror}, true
+}
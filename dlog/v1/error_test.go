@@ -0,0 +1,56 @@
+package dlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/don-nv/go-dpkg/derr/v1"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestData_Error(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		l   = New()
+	)
+
+	l.zero = zerolog.New(&buf)
+
+	var err = fmt.Errorf("handling: %w", derr.New("order_not_found").
+		Msg("order is not found").
+		Status(http.StatusNotFound).
+		With("order_id", 42).
+		Wrap(errors.New("no rows")),
+	)
+
+	l.E().Any("err", err).Write("msg")
+
+	var entry struct {
+		Err struct {
+			Message string         `json:"message"`
+			Code    string         `json:"code"`
+			Status  int            `json:"status"`
+			Public  string         `json:"public"`
+			Fields  map[string]any `json:"fields"`
+			Stack   []string       `json:"stack"`
+		} `json:"err"`
+	}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, err.Error(), entry.Err.Message)
+	require.Equal(t, "order_not_found", entry.Err.Code)
+	require.Equal(t, http.StatusNotFound, entry.Err.Status)
+	require.Equal(t, "order is not found", entry.Err.Public)
+	require.Equal(t, map[string]any{"order_id": float64(42)}, entry.Err.Fields)
+	require.NotEmpty(t, entry.Err.Stack)
+	require.True(t, strings.HasPrefix(entry.Err.Stack[0], "github.com/don-nv/go-dpkg/dlog/v1.TestData_Error "))
+
+	buf.Reset()
+	l.E().Any("err", errors.New("plain")).Write("msg")
+	require.Contains(t, buf.String(), `"err":"plain"`)
+}
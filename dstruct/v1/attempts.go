@@ -54,7 +54,10 @@ func (a *AttemptsV1Sync) Exceeded() bool {
 	return a.attempts.Exceeded()
 }
 
-// AwaitDelay - awaits delay between current and next attempt. If 'ctx' ends before delay, context error is returned.
+/*
+AwaitDelay - awaits delay between current and next attempt. If 'ctx' ends before delay, context error is returned. Delay
+is measured by 'ctx' Clock, see dtime.AwaitDelay().
+*/
 func (a *AttemptsV1Sync) AwaitDelay(ctx context.Context) error {
 	return dtime.AwaitDelay(ctx, a.Delay())
 }
@@ -119,7 +122,10 @@ func (a *AttemptsV1) Exceeded() bool {
 	return a.AttemptN() >= a.AttemptsN()
 }
 
-// AwaitDelay - awaits delay between current and next attempt. If 'ctx' ends before delay, context error is returned.
+/*
+AwaitDelay - awaits delay between current and next attempt. If 'ctx' ends before delay, context error is returned. Delay
+is measured by 'ctx' Clock, see dtime.AwaitDelay().
+*/
 func (a *AttemptsV1) AwaitDelay(ctx context.Context) error {
 	return dtime.AwaitDelay(ctx, a.Delay())
 }
@@ -12,13 +12,16 @@ import (
 type Group struct {
 	group *errgroup.Group
 	doneC <-chan struct{}
+	// values - holds values of a context Group is created with. See DeriveContext().
+	values context.Context
 }
 
 // NewGroup - returns a new Group. The Group is Done() once 'ctx' is closed. May be used several times.
 func NewGroup(ctx context.Context) Group {
 	return Group{
-		group: &errgroup.Group{},
-		doneC: ctx.Done(),
+		group:  &errgroup.Group{},
+		doneC:  ctx.Done(),
+		values: dctx.WithoutCancel(ctx),
 	}
 }
 
@@ -30,8 +33,9 @@ func NewOneTimeGroup(ctx context.Context) Group {
 	var group, groupCtx = errgroup.WithContext(ctx)
 
 	return Group{
-		group: group,
-		doneC: groupCtx.Done(),
+		group:  group,
+		doneC:  groupCtx.Done(),
+		values: dctx.WithoutCancel(ctx),
 	}
 }
 
@@ -145,10 +149,13 @@ func (g Group) GoUntilWait(name string, f func(ctx context.Context) error) {
 /*
 DeriveContext - returns new context that will be cancelled if Group exits. A special care should be taken not to
 forget to release new context by calling returned cancel function if Group lifetime is long. Context deriving without
-control may lead to goroutines leaking.
+control may lead to goroutines leaking. New context has values of a context Group is created with, but a new go id
+having the former one as a parent, see dctx.WithNewGoID().
 */
 func (g Group) DeriveContext() (context.Context, context.CancelFunc) {
-	var ctx, cancel = dctx.NewTTL()
+	var ctx, cancel = dctx.WithTTLCancel(
+		dctx.WithNewGoID(g.values),
+	)
 
 	ctx, _ = dctx.WithTTLC(ctx, g.doneC)
 
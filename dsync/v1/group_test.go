@@ -0,0 +1,33 @@
+package dsync_test
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dctx/v1"
+	"github.com/don-nv/go-dpkg/dsync/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGroup_Go(t *testing.T) {
+	var (
+		ctx   = dctx.New(dctx.OptionWithNewXRequestID())
+		group = dsync.NewGroup(ctx)
+		ctxC  = make(chan context.Context, 2)
+	)
+
+	for i := 0; i < 2; i++ {
+		group.Go(func(ctx context.Context) error {
+			ctxC <- ctx
+			return nil
+		})
+	}
+
+	require.NoError(t, group.Wait())
+
+	var first, second = <-ctxC, <-ctxC
+	require.Equal(t, dctx.GoID(ctx), dctx.ParentGoID(first))
+	require.Equal(t, dctx.GoID(ctx), dctx.ParentGoID(second))
+	require.NotEqual(t, dctx.GoID(first), dctx.GoID(second))
+	require.Equal(t, dctx.XRequestID(ctx), dctx.XRequestID(first))
+	require.Error(t, first.Err())
+}
@@ -0,0 +1,267 @@
+package dtime
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock - is a source of time. ClockReal is the system one, while FakeClock is advanced manually, e.g. in tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	After(d time.Duration) <-chan time.Time
+	Since(t time.Time) time.Duration
+}
+
+// ClockReal - is a Clock using "time" package.
+var ClockReal Clock = clockReal{}
+
+type clockReal struct{}
+
+func (clockReal) Now() time.Time {
+	return time.Now()
+}
+
+func (clockReal) NewTimer(d time.Duration) Timer {
+	return NewTimer(d)
+}
+
+func (clockReal) NewTicker(d time.Duration) Ticker {
+	return NewTicker(d)
+}
+
+func (clockReal) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (clockReal) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+type keyClock struct{}
+
+// WithClock - creates 'ctx' child, adds 'clock' and returns it. See ClockOf().
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, keyClock{}, clock)
+}
+
+// ClockOf - returns Clock added by WithClock() or ClockReal if there is none.
+func ClockOf(ctx context.Context) Clock {
+	var clock, ok = ctx.Value(keyClock{}).(Clock)
+	if !ok {
+		return ClockReal
+	}
+
+	return clock
+}
+
+/*
+FakeClock - is a Clock, which time is changed by FakeClock.Advance() only. Its timers and tickers fire once time is
+advanced up to or beyond their expiration, in expiration order. As well as "time" package tickers, fake ones drop ticks
+if they are not received. It is safe to be used concurrently.
+*/
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	var w = c.newWaiter(d, 0)
+
+	return Timer{
+		c:     w.c,
+		timer: w,
+	}
+}
+
+// NewTicker - panics if 'd' <= 0, as well as time.NewTicker().
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+
+	var w = c.newWaiter(d, d)
+
+	return Ticker{
+		c:      w.c,
+		ticker: fakeTicker{w: w},
+	}
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Advance - moves time forward by 'd' firing timers and tickers expired meanwhile. 'd' < 1 is ignored.
+func (c *FakeClock) Advance(d time.Duration) {
+	if d < 1 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var until = c.now.Add(d)
+
+	for {
+		var i = c.soonest()
+		if i < 0 || c.waiters[i].at.After(until) {
+			break
+		}
+
+		var w = c.waiters[i]
+
+		c.now = w.at
+		w.fire()
+
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			c.waiters = slices.Delete(c.waiters, i, i+1)
+		}
+	}
+
+	c.now = until
+}
+
+/*
+Waiters - returns number of active timers and tickers. It is useful to await a goroutine to create one before
+advancing time.
+*/
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+func (c *FakeClock) newWaiter(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var w = &fakeWaiter{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		period: period,
+	}
+
+	c.schedule(w, d)
+
+	return w
+}
+
+// schedule - (re)schedules 'w' to fire in 'd'. Non-positive 'd' fires 'w' at once. It must be called under lock.
+func (c *FakeClock) schedule(w *fakeWaiter, d time.Duration) {
+	if d <= 0 && w.period == 0 {
+		w.fire()
+		return
+	}
+
+	w.at = c.now.Add(d)
+
+	if !slices.Contains(c.waiters, w) {
+		c.waiters = append(c.waiters, w)
+	}
+}
+
+// unschedule - returns false if 'w' was not scheduled. It must be called under lock.
+func (c *FakeClock) unschedule(w *fakeWaiter) bool {
+	var i = slices.Index(c.waiters, w)
+	if i < 0 {
+		return false
+	}
+
+	c.waiters = slices.Delete(c.waiters, i, i+1)
+
+	return true
+}
+
+// soonest - returns index of a waiter to fire first or -1 if there are none. It must be called under lock.
+func (c *FakeClock) soonest() int {
+	var i = -1
+
+	for j, w := range c.waiters {
+		if i < 0 || w.at.Before(c.waiters[i].at) {
+			i = j
+		}
+	}
+
+	return i
+}
+
+// fakeWaiter - is a FakeClock timer or ticker. Ticker has a positive period.
+type fakeWaiter struct {
+	clock  *FakeClock
+	c      chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+// fire - sends current time unless previous one is not received yet. It must be called under lock.
+func (w *fakeWaiter) fire() {
+	select {
+	case w.c <- w.clock.now:
+	default:
+	}
+}
+
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	return w.clock.unschedule(w)
+}
+
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	var active = w.clock.unschedule(w)
+	w.clock.schedule(w, d)
+
+	return active
+}
+
+type fakeTicker struct {
+	w *fakeWaiter
+}
+
+func (t fakeTicker) Stop() {
+	t.w.Stop()
+}
+
+// Reset - panics if 'd' <= 0, as well as time.Ticker.Reset().
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock ticker Reset")
+	}
+
+	t.w.clock.mu.Lock()
+	defer t.w.clock.mu.Unlock()
+
+	t.w.period = d
+	t.w.clock.schedule(t.w, d)
+}
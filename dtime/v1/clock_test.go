@@ -0,0 +1,89 @@
+package dtime_test
+
+import (
+	"context"
+	"github.com/don-nv/go-dpkg/dtime/v1"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFakeClock_Timer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock = dtime.NewFakeClock(start)
+		timer = clock.NewTimer(time.Second)
+	)
+
+	clock.Advance(time.Second - 1)
+	requireNotFired(t, timer.C())
+
+	clock.Advance(1)
+	require.Equal(t, start.Add(time.Second), <-timer.C())
+	require.Equal(t, 0, clock.Waiters())
+
+	require.False(t, timer.Reset(time.Minute))
+	require.True(t, timer.Stop())
+
+	clock.Advance(time.Hour)
+	requireNotFired(t, timer.C())
+	require.Equal(t, time.Hour+time.Second, clock.Since(start))
+
+	t.Log("non-positive reset fires at once")
+	require.False(t, timer.Reset(0))
+	require.Equal(t, start.Add(time.Hour+time.Second), <-timer.C())
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	t.Parallel()
+
+	var (
+		start  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = dtime.NewFakeClock(start)
+		ticker = clock.NewTicker(time.Second)
+	)
+	defer ticker.Stop()
+
+	clock.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), <-ticker.C())
+
+	// Ticks not received are dropped.
+	clock.Advance(3 * time.Second)
+	require.Equal(t, start.Add(2*time.Second), <-ticker.C())
+	requireNotFired(t, ticker.C())
+
+	ticker.Reset(time.Minute)
+	clock.Advance(time.Minute)
+	require.Equal(t, start.Add(4*time.Second+time.Minute), <-ticker.C())
+}
+
+func TestAwaitDelay_FakeClock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = dtime.NewFakeClock(time.Now())
+		ctx   = dtime.WithClock(context.Background(), clock)
+		errC  = make(chan error, 1)
+	)
+
+	go func() {
+		errC <- dtime.AwaitDelay(ctx, time.Hour)
+	}()
+
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	clock.Advance(time.Hour)
+	require.NoError(t, <-errC)
+}
+
+func requireNotFired(t *testing.T, c <-chan time.Time) {
+	t.Helper()
+
+	select {
+	case v := <-c:
+		t.Fatalf("unexpected fire at %s", v)
+	default:
+	}
+}
@@ -10,20 +10,23 @@ func SinceMs[T float64](t time.Time) T {
 	return T(time.Since(t).Milliseconds())
 }
 
-// AwaitDelay - awaits 'd'. If 'ctx' is canceled before 'd', context error is returned.
+/*
+AwaitDelay - awaits 'd' measured by 'ctx' Clock, see ClockOf(). If 'ctx' is canceled before 'd', context error is
+returned.
+*/
 func AwaitDelay(ctx context.Context, d time.Duration) error {
 	if d < 1 {
 		return fmt.Errorf("non-positive %q delay", d)
 	}
 
-	var ticker = NewTicker(d)
-	defer ticker.Stop()
+	var timer = ClockOf(ctx).NewTimer(d)
+	defer timer.Stop()
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 
-	case <-ticker.C():
+	case <-timer.C():
 		return nil
 	}
 }
@@ -7,24 +7,34 @@ import (
 )
 
 type Ticker struct {
-	ticker *time.Ticker
+	c      <-chan time.Time
+	ticker ticker
+}
+
+// ticker - is implemented by *time.Ticker and a FakeClock ticker.
+type ticker interface {
+	Stop()
+	Reset(d time.Duration)
 }
 
 func NewTicker(d time.Duration) Ticker {
+	var ticker = time.NewTicker(d)
+
 	return Ticker{
-		ticker: time.NewTicker(d),
+		c:      ticker.C,
+		ticker: ticker,
 	}
 }
 
 func (t Ticker) C() <-chan time.Time {
-	return t.ticker.C
+	return t.c
 }
 
 // Stop - is the same as time.Ticker.Stop(), but drains tickers' channel after.
 func (t Ticker) Stop() {
 	t.ticker.Stop()
 
-	dchan.Drain(context.Background(), t.ticker.C)
+	dchan.Drain(context.Background(), t.c)
 }
 
 // Reset - is the same as time.Ticker.Reset().
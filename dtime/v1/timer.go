@@ -7,19 +7,27 @@ import (
 )
 
 type Timer struct {
-	timer *time.Timer
+	c     <-chan time.Time
+	timer timer
+}
+
+// timer - is implemented by *time.Timer and a FakeClock timer.
+type timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
 }
 
 func NewTimer(d time.Duration) Timer {
 	var timer = time.NewTimer(d)
 
 	return Timer{
+		c:     timer.C,
 		timer: timer,
 	}
 }
 
 func (t Timer) C() <-chan time.Time {
-	return t.timer.C
+	return t.c
 }
 
 // Stop - is the same as time.Timer.Stop(), but drains timers' channel after.
@@ -27,19 +35,20 @@ func (t Timer) Stop() bool {
 	var ok = t.timer.Stop()
 
 	if !ok {
-		dchan.Drain(context.Background(), t.timer.C)
+		dchan.Drain(context.Background(), t.c)
 	}
 
 	return ok
 }
 
-// Reset - is the same as time.Timer.Reset(), but drains timers' channel after.
+/*
+Reset - is the same as time.Timer.Reset(), but stops timer and drains its channel before, so neither a stale value is
+received, nor a value sent once 'd' passes is lost, e.g. if 'd' <= 0.
+*/
 func (t Timer) Reset(d time.Duration) bool {
-	var ok = t.timer.Reset(d)
+	var ok = t.Stop()
 
-	if !ok {
-		dchan.Drain(context.Background(), t.timer.C)
-	}
+	t.timer.Reset(d)
 
 	return ok
 }